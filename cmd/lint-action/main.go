@@ -23,6 +23,7 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/abcxyz/secure-setup-terraform/pkg/cli"
 	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
 	"github.com/abcxyz/secure-setup-terraform/pkg/version"
 )
@@ -37,7 +38,7 @@ FLAGS
 func main() {
 	if err := realMain(); err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 }
 
@@ -52,6 +53,8 @@ func realMain() error {
 		f.PrintDefaults()
 	}
 	showVersion := f.Bool("version", false, "display version information")
+	var lintFlags cli.LintFlags
+	lintFlags.Register(f)
 
 	if err := f.Parse(os.Args[1:]); err != nil {
 		return fmt.Errorf("failed to parse flags: %w", err)
@@ -61,14 +64,5 @@ func realMain() error {
 		return nil
 	}
 
-	// The linter needs at least one file or directory
-	args := f.Args()
-	if got := len(args); got < 1 {
-		return fmt.Errorf("expected at least one argument, got %d", got)
-	}
-
-	if err := linter.RunLinter(ctx, args, &linter.GitHubActionLinter{}); err != nil {
-		return fmt.Errorf("error running linter %w", err)
-	}
-	return nil
+	return lintFlags.Run(ctx, f.Args(), []linter.Linter{&linter.GitHubActionLinter{}})
 }
@@ -0,0 +1,236 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/cli"
+	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
+	"github.com/abcxyz/secure-setup-terraform/pkg/lsp"
+	"github.com/abcxyz/secure-setup-terraform/pkg/verify"
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+const rootHelp = `
+Usage: secure-terraform <command> [flags] [args]
+
+COMMANDS
+  lint terraform   Lint terraform files
+  lint actions     Lint GitHub Actions workflow files
+  lint all         Run every linter in a single walk
+  lsp              Run a language server on stdin and stdout
+  rules            List the available rules
+  verify           Verify the checksum of the terraform binary
+  version          Display version information
+`
+
+const lintHelp = `
+Usage: secure-terraform lint <terraform|actions|all> [flags] <file|directory>...
+
+EXAMPLES
+  secure-terraform lint all .
+  secure-terraform lint terraform -format=sarif -output=results.sarif infra/
+FLAGS
+`
+
+const lspHelp = `
+Usage: secure-terraform lsp [flags]
+
+Runs a Language Server Protocol server over stdin and stdout. Violations in
+open Terraform and workflow files are published as diagnostics as they are
+edited, and violations with a fix are offered as quick fixes.
+
+FLAGS
+`
+
+const verifyHelp = `
+Usage: secure-terraform verify [flags] [terraform binary]
+
+Verifies the sha256 checksum of a terraform binary against the checksums file
+published with each release. When no binary is given, terraform-bin (installed
+by the terraform wrapper) or terraform is looked up on the PATH.
+
+FLAGS
+`
+
+// linterSets maps the argument of the lint command to the linters it runs.
+var linterSets = map[string]func() []linter.Linter{
+	"terraform": func() []linter.Linter {
+		return []linter.Linter{&linter.TerraformLinter{}}
+	},
+	"actions": func() []linter.Linter {
+		return []linter.Linter{&linter.GitHubActionLinter{}}
+	},
+	"all": func() []linter.Linter {
+		return []linter.Linter{&linter.TerraformLinter{}, &linter.GitHubActionLinter{}}
+	},
+}
+
+func main() {
+	if err := realMain(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(cli.ExitCode(err))
+	}
+}
+
+func realMain() error {
+	ctx, done := signal.NotifyContext(context.Background(),
+		syscall.SIGINT, syscall.SIGTERM)
+	defer done()
+
+	args := os.Args[1:]
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(rootHelp))
+		return fmt.Errorf("missing command")
+	}
+
+	switch cmd, rest := args[0], args[1:]; cmd {
+	case "lint":
+		return runLint(ctx, rest)
+	case "lsp":
+		return runLSP(ctx, rest)
+	case "rules":
+		return runRules()
+	case "verify":
+		return runVerify(rest)
+	case "version":
+		fmt.Fprintln(os.Stderr, version.HumanVersion)
+		return nil
+	case "help", "-h", "-help", "--help":
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(rootHelp))
+		return nil
+	default:
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(rootHelp))
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
+
+func runLint(ctx context.Context, args []string) error {
+	f := flag.NewFlagSet("lint", flag.ExitOnError)
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\n", strings.TrimSpace(lintHelp))
+		f.PrintDefaults()
+	}
+	var lintFlags cli.LintFlags
+	lintFlags.Register(f)
+
+	if len(args) == 0 {
+		f.Usage()
+		return fmt.Errorf("expected one of terraform, actions or all")
+	}
+	linters, ok := linterSets[args[0]]
+	if !ok {
+		f.Usage()
+		return fmt.Errorf("unknown linter %q, expected one of terraform, actions or all", args[0])
+	}
+
+	if err := f.Parse(args[1:]); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	return lintFlags.Run(ctx, f.Args(), linters())
+}
+
+func runLSP(ctx context.Context, args []string) error {
+	f := flag.NewFlagSet("lsp", flag.ExitOnError)
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\n", strings.TrimSpace(lspHelp))
+		f.PrintDefaults()
+	}
+	configPath := f.String("config", "", fmt.Sprintf("path to the project configuration file (default: the nearest %s above each file)", linter.ConfigFileName))
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	if f.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %q", f.Args())
+	}
+	return lsp.NewServer(linterSets["all"](), *configPath).Serve(ctx, os.Stdin, os.Stdout)
+}
+
+func runRules() error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSEVERITY\tDESCRIPTION")
+	for _, r := range linter.Rules() {
+		info := r.Info()
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.ID, info.Name, info.DefaultSeverity, info.Description)
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write rules: %w", err)
+	}
+	return nil
+}
+
+func runVerify(args []string) error {
+	f := flag.NewFlagSet("verify", flag.ExitOnError)
+	f.Usage = func() {
+		fmt.Fprintf(os.Stderr, "%s\n\n", strings.TrimSpace(verifyHelp))
+		f.PrintDefaults()
+	}
+	checksumsPath := f.String("checksums", "terraform-checksums.json", "path to the terraform checksums file")
+	tfVersion := f.String("terraform-version", "", "version of terraform to verify (required)")
+	goos := f.String("os", runtime.GOOS, "operating system of the terraform binary")
+	goarch := f.String("arch", runtime.GOARCH, "architecture of the terraform binary")
+
+	if err := f.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	if *tfVersion == "" {
+		return fmt.Errorf("-terraform-version is required")
+	}
+
+	var binary string
+	switch rest := f.Args(); len(rest) {
+	case 0:
+		// The terraform wrapper installs the actual binary as terraform-bin,
+		// prefer it over the wrapper script.
+		for _, name := range []string{"terraform-bin", "terraform"} {
+			if p, err := exec.LookPath(name); err == nil {
+				binary = p
+				break
+			}
+		}
+		if binary == "" {
+			return fmt.Errorf("terraform binary not found on PATH")
+		}
+	case 1:
+		binary = rest[0]
+	default:
+		return fmt.Errorf("expected at most one argument, got %d", len(rest))
+	}
+
+	checksums, err := verify.LoadChecksums(*checksumsPath)
+	if err != nil {
+		return err
+	}
+	want, err := checksums.Find(*tfVersion, *goos, *goarch)
+	if err != nil {
+		return err
+	}
+	if err := verify.Binary(binary, want); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%s: OK\n", binary)
+	return nil
+}
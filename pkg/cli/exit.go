@@ -0,0 +1,66 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import "github.com/abcxyz/secure-setup-terraform/pkg/linter"
+
+// Exit codes shared by every command.
+const (
+	// ExitClean means the run completed and found nothing that fails it.
+	ExitClean = 0
+
+	// ExitViolations means the run completed and found violations at or above
+	// the -fail-on severity.
+	ExitViolations = 1
+
+	// ExitFailure means the tool itself failed, for example because of invalid
+	// flags or configuration, or because a file could not be read or parsed.
+	ExitFailure = 2
+)
+
+// ExitCode returns the exit code for the error returned by a command.
+// Violations exit with ExitViolations; any other error, including when it is
+// combined with violations, exits with ExitFailure.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitClean
+	case onlyViolations(err):
+		return ExitViolations
+	default:
+		return ExitFailure
+	}
+}
+
+// onlyViolations returns true if err consists only of *linter.ViolationsError
+// values, possibly wrapped or joined.
+func onlyViolations(err error) bool {
+	switch e := err.(type) {
+	case *linter.ViolationsError:
+		return true
+	case interface{ Unwrap() []error }:
+		errs := e.Unwrap()
+		for _, err := range errs {
+			if !onlyViolations(err) {
+				return false
+			}
+		}
+		return len(errs) > 0
+	case interface{ Unwrap() error }:
+		return onlyViolations(e.Unwrap())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	violations := &linter.ViolationsError{Count: 2}
+	parse := &linter.LintFailedError{Count: 1}
+
+	cases := []struct {
+		name   string
+		err    error
+		expect int
+	}{
+		{
+			name:   "clean",
+			expect: ExitClean,
+		},
+		{
+			name:   "violations",
+			err:    fmt.Errorf("error running linter %w", violations),
+			expect: ExitViolations,
+		},
+		{
+			name:   "parse errors",
+			err:    fmt.Errorf("error running linter %w", parse),
+			expect: ExitFailure,
+		},
+		{
+			name:   "violations and parse errors",
+			err:    fmt.Errorf("error running linter %w", errors.Join(violations, parse)),
+			expect: ExitFailure,
+		},
+		{
+			name:   "tool failure",
+			err:    errors.New("failed to load config"),
+			expect: ExitFailure,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := ExitCode(tc.err); got != tc.expect {
+				t.Errorf("expected exit code %d, got %d", tc.expect, got)
+			}
+		})
+	}
+}
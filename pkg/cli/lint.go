@@ -0,0 +1,276 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli contains the command line handling shared by the linter
+// binaries.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
+)
+
+// LintFlags are the flags shared by every lint command.
+type LintFlags struct {
+	format            string
+	output            string
+	workers           int
+	excludes          stringSliceFlag
+	includes          stringSliceFlag
+	noDefaultExcludes bool
+	gitignore         bool
+	followSymlinks    bool
+	failOnParseErrors bool
+	configPath        string
+	baseline          string
+	writeBaseline     string
+	listSuppressions  bool
+	since             string
+	wholeFile         bool
+	stdinFilename     string
+	cache             bool
+	cacheDir          string
+	clearCache        bool
+	fix               bool
+	diff              bool
+	failOn            string
+}
+
+// Register adds the lint flags to f.
+func (l *LintFlags) Register(f *flag.FlagSet) {
+	f.StringVar(&l.format, "format", "",
+		fmt.Sprintf("output format, one of %q (default \"github\" when GITHUB_ACTIONS=true, otherwise \"text\")", linter.Formats))
+	f.StringVar(&l.output, "output", "", "write results to this file instead of stdout")
+	f.IntVar(&l.workers, "workers", runtime.GOMAXPROCS(0), "number of files to read and parse concurrently")
+	f.Var(&l.excludes, "exclude", "glob of files or directories to skip, relative to each argument (may be repeated)")
+	f.Var(&l.includes, "include", "glob of files to lint, relative to each argument (may be repeated)")
+	f.BoolVar(&l.noDefaultExcludes, "no-default-excludes", false, fmt.Sprintf("do not skip %q directories", linter.DefaultExcludes))
+	f.BoolVar(&l.gitignore, "gitignore", false, "skip files and directories ignored by .gitignore files")
+	f.BoolVar(&l.followSymlinks, "follow-symlinks", false, "follow symlinks found while walking directories")
+	f.StringVar(&l.failOn, "fail-on", string(linter.SeverityError), fmt.Sprintf("lowest severity that fails the run, one of %q", linter.Severities))
+	f.BoolVar(&l.failOnParseErrors, "fail-on-parse-errors", true, "fail the run when a file cannot be read or parsed")
+	f.StringVar(&l.configPath, "config", "", fmt.Sprintf("path to the project configuration file (default: the nearest %s above the first argument)", linter.ConfigFileName))
+	f.StringVar(&l.baseline, "baseline", "", "path to a baseline file; violations in the baseline are reported as warnings and do not fail the run")
+	f.StringVar(&l.writeBaseline, "write-baseline", "", "write a baseline of all current violations to this file")
+	f.StringVar(&l.since, "since", "", "only lint files changed since this git ref and report violations on added or modified lines")
+	f.BoolVar(&l.wholeFile, "whole-file", false, "with -since, report every violation in the changed files")
+	f.StringVar(&l.stdinFilename, "stdin-filename", "", "when the argument is \"-\", the file name to lint standard input as, for example \"main.tf\"")
+	f.BoolVar(&l.cache, "cache", false, "reuse results for files that have not changed since a previous run")
+	f.StringVar(&l.cacheDir, "cache-dir", "", "directory to store cached results in (default: secure-setup-terraform in the user cache directory)")
+	f.BoolVar(&l.clearCache, "clear-cache", false, "remove all cached results before linting; with no arguments, only clear the cache")
+	f.BoolVar(&l.fix, "fix", false, "apply the fixes of violations that have one and rewrite the files")
+	f.BoolVar(&l.diff, "diff", false, "print the changes -fix would make as a unified diff without changing any files")
+	f.BoolVar(&l.listSuppressions, "list-suppressions", false, "list every suppression comment and its status instead of reporting violations")
+}
+
+// Run loads the project configuration and runs the linters over the paths in
+// args, writing results as requested by the flags. A single argument of "-"
+// lints standard input and a single .tar.gz, .tgz or .zip argument lints the
+// contents of the archive.
+func (l *LintFlags) Run(ctx context.Context, args []string, linters []linter.Linter) (retErr error) {
+	outputFormat, err := linter.ParseFormat(l.format)
+	if err != nil {
+		return fmt.Errorf("invalid -format: %w", err)
+	}
+
+	failOn, err := linter.ParseSeverity(l.failOn)
+	if err != nil {
+		return fmt.Errorf("invalid -fail-on: %w", err)
+	}
+
+	cache, err := l.openCache()
+	if err != nil {
+		return err
+	}
+	if l.clearCache {
+		if err := cache.Clear(); err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return nil
+		}
+	}
+	if !l.cache {
+		cache = nil
+	}
+
+	// The linter needs at least one file or directory
+	if got := len(args); got < 1 {
+		return fmt.Errorf("expected at least one argument, got %d", got)
+	}
+
+	fsys, paths, cfg, err := l.source(args)
+	if err != nil {
+		return err
+	}
+
+	stdout := os.Stdout
+	if l.output != "" {
+		out, err := os.Create(l.output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() {
+			if err := out.Close(); err != nil && retErr == nil {
+				retErr = fmt.Errorf("failed to close output file: %w", err)
+			}
+		}()
+		stdout = out
+	}
+
+	if err := linter.RunLinters(ctx, paths, linters, &linter.Options{
+		FS:     fsys,
+		Format: outputFormat,
+		Stdout: stdout,
+
+		ListSuppressions: l.listSuppressions,
+		Baseline:         l.baseline,
+		WriteBaseline:    l.writeBaseline,
+		Config:           cfg,
+		Workers:          l.workers,
+
+		Exclude:           l.excludes,
+		Include:           l.includes,
+		NoDefaultExcludes: l.noDefaultExcludes,
+		Gitignore:         l.gitignore,
+		FollowSymlinks:    l.followSymlinks,
+		AllowParseErrors:  !l.failOnParseErrors,
+		FailOn:            failOn,
+		Since:             l.since,
+		SinceWholeFile:    l.wholeFile,
+		Cache:             cache,
+		Fix:               l.fix,
+		Diff:              l.diff,
+	}); err != nil {
+		return fmt.Errorf("error running linter %w", err)
+	}
+	return nil
+}
+
+// openCache opens the cache directory if caching was requested, or the cache
+// is being cleared. It returns nil otherwise.
+func (l *LintFlags) openCache() (*linter.Cache, error) {
+	if !l.cache && !l.clearCache {
+		return nil, nil
+	}
+	dir := l.cacheDir
+	if dir == "" {
+		def, err := linter.DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = def
+	}
+	cache, err := linter.OpenCache(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	return cache, nil
+}
+
+// source returns the file system and paths to lint, and the project
+// configuration that applies to them. fsys is nil for the local file system.
+func (l *LintFlags) source(args []string) (fsys fs.FS, paths []string, cfg *linter.Config, err error) {
+	for _, arg := range args {
+		if (arg == "-" || linter.IsArchive(arg)) && len(args) != 1 {
+			return nil, nil, nil, fmt.Errorf("%q must be the only argument", arg)
+		}
+	}
+
+	switch arg := args[0]; {
+	case arg == "-":
+		if l.stdinFilename == "" {
+			return nil, nil, nil, fmt.Errorf("-stdin-filename is required when linting standard input")
+		}
+		name, err := stdinName(l.stdinFilename)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		content, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to read standard input: %w", err)
+		}
+		cfg, err := linter.ResolveConfig(l.configPath, ".")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return linter.NewMemFS(map[string][]byte{name: content}), []string{name}, cfg, nil
+
+	case linter.IsArchive(arg):
+		fsys, err := linter.OpenArchive(arg)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// A configuration file at the root of the archive applies to it, the
+		// configuration next to the archive does not.
+		if l.configPath != "" {
+			cfg, err = linter.LoadConfig(l.configPath)
+		} else if _, statErr := fs.Stat(fsys, linter.ConfigFileName); statErr == nil {
+			cfg, err = linter.LoadConfigFS(fsys, linter.ConfigFileName)
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return fsys, []string{"."}, cfg, nil
+
+	default:
+		cfg, err := linter.ResolveConfig(l.configPath, arg)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return nil, args, cfg, nil
+	}
+}
+
+// stdinName returns the path, relative to the current directory, that
+// standard input is linted as. Editors and hooks usually pass absolute paths,
+// which are accepted as long as they are within the current directory.
+func stdinName(filename string) (string, error) {
+	if filepath.IsAbs(filename) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", fmt.Errorf("failed to get current directory: %w", err)
+		}
+		rel, err := filepath.Rel(wd, filename)
+		if err != nil {
+			return "", fmt.Errorf("invalid -stdin-filename %q: %w", filename, err)
+		}
+		filename = rel
+	}
+	name := path.Clean(filepath.ToSlash(filename))
+	if !fs.ValidPath(name) {
+		return "", fmt.Errorf("invalid -stdin-filename %q, must be within the current directory", filename)
+	}
+	return name, nil
+}
+
+// stringSliceFlag is a flag that can be repeated to build a list of values.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string { return strings.Join(*s, ",") }
+
+func (s *stringSliceFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
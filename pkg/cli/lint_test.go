@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStdinName(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		filename  string
+		expect    string
+		wantError bool
+	}{
+		{
+			name:     "relative",
+			filename: "main.tf",
+			expect:   "main.tf",
+		},
+		{
+			name:     "relative with dot",
+			filename: "./modules/net/../db/main.tf",
+			expect:   "modules/db/main.tf",
+		},
+		{
+			name:     "absolute",
+			filename: filepath.Join(wd, "modules", "main.tf"),
+			expect:   "modules/main.tf",
+		},
+		{
+			name:      "absolute outside",
+			filename:  filepath.Join(filepath.Dir(wd), "other", "main.tf"),
+			wantError: true,
+		},
+		{
+			name:      "relative outside",
+			filename:  "../main.tf",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := stdinName(tc.filename)
+			if tc.wantError != (err != nil) {
+				t.Fatalf("expected error want: %t, got: %v", tc.wantError, err)
+			}
+			if got != tc.expect {
+				t.Errorf("expected %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
@@ -16,86 +16,286 @@ package linter
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
 	"gopkg.in/yaml.v3"
 )
 
 const tokenSetupTerraform = "setup-terraform"
 
-var actionSelectors = []string{".yml", ".yaml"}
+const (
+	// optionActions is the setup-terraform rule option listing the actions to
+	// flag.
+	optionActions = "actions"
 
-type GitHubActionLinter struct{}
+	// optionReplacement is the setup-terraform rule option naming the action
+	// reference that fixes replace flagged actions with.
+	optionReplacement = "replacement"
+)
+
+// secureSetupTerraformAction is the action that replaces setup-terraform.
+const secureSetupTerraformAction = "abcxyz/secure-setup-terraform"
+
+var (
+	actionSelectors = []string{".yml", ".yaml"}
+
+	defaultSetupTerraformActions = []string{"hashicorp/setup-terraform"}
+)
+
+// GitHubActionLinter parses GitHub Actions workflow files and runs every
+// enabled WorkflowRule against them.
+type GitHubActionLinter struct {
+	cfg *Config
+}
+
+func (tfl *GitHubActionLinter) configure(cfg *Config) { tfl.cfg = cfg }
+
+// Workflow is a parsed GitHub Actions workflow file.
+type Workflow struct {
+	// Path is the path of the file.
+	Path string
+
+	// Content is the raw content of the file.
+	Content []byte
+
+	// Root is the top-level mapping of the document.
+	Root *yaml.Node
+
+	// Jobs are the jobs of the workflow, in file order.
+	Jobs []*WorkflowJob
+}
 
-// FindViolations inspects a set of bytes that represent a YAML document that defines
-// a GitHub action workflow looking for steps that use the 'hashicorp/setup-terraform'
-// action.
+// WorkflowJob is a single job of a workflow.
+type WorkflowJob struct {
+	// Name is the key of the job in the jobs mapping.
+	Name string
+
+	// Node is the mapping that defines the job.
+	Node *yaml.Node
+
+	// Steps are the steps of the job, in file order.
+	Steps []*WorkflowStep
+}
+
+// WorkflowStep is a single step of a job.
+type WorkflowStep struct {
+	// Job is the name of the job the step belongs to.
+	Job string
+
+	// Index is the 0-based position of the step within the job.
+	Index int
+
+	// Node is the mapping that defines the step.
+	Node *yaml.Node
+
+	// Uses is the value of the "uses" key, or nil if the step runs a command.
+	Uses *yaml.Node
+}
+
+// Object returns the name of the step, for example "jobs.build.steps[0]".
+func (s *WorkflowStep) Object() string {
+	return fmt.Sprintf("jobs.%s.steps[%d]", s.Job, s.Index)
+}
+
+// FindViolations parses a set of bytes that represent a YAML document that
+// defines a GitHub action workflow and runs the enabled workflow rules against
+// it.
 func (tfl *GitHubActionLinter) FindViolations(content []byte, path string) ([]*ViolationInstance, error) {
+	violations, _, err := tfl.findScopedViolations(content, path)
+	return violations, err
+}
+
+func (tfl *GitHubActionLinter) findScopedViolations(content []byte, path string) ([]*ViolationInstance, scopes, error) {
+	workflow, err := parseWorkflow(content, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if workflow == nil {
+		return nil, nil, nil
+	}
+
+	var violations []*ViolationInstance
+	for _, r := range enabledRules(tfl.cfg) {
+		rule, ok := r.(WorkflowRule)
+		if !ok {
+			continue
+		}
+		pass := &Pass{Path: path, info: rule.Info(), cfg: tfl.cfg}
+		rule.CheckWorkflow(pass, workflow)
+		violations = append(violations, pass.violations...)
+	}
+	sortViolations(violations)
+	return violations, &yamlScopes{lines: splitLines(content)}, nil
+}
+
+// parseWorkflow parses content into a Workflow. It returns nil if the document
+// is empty.
+func parseWorkflow(content []byte, path string) (*Workflow, error) {
 	reader := bytes.NewReader(content)
 	node, err := parseYAML(reader)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+		if errors.Is(err, io.EOF) {
+			// Empty documents have nothing to lint.
+			return nil, nil
+		}
+		return nil, yamlDiagnostic(path, errors.Unwrap(err))
 	}
 	if node == nil {
 		return nil, nil
 	}
 	if node.Kind != yaml.DocumentNode {
-		return nil, fmt.Errorf("expected document node, got %v", node.Kind)
+		return nil, &Diagnostic{Path: path, Line: node.Line, Column: node.Column, Message: fmt.Sprintf("expected document node, got %v", node.Kind)}
 	}
 
-	var violations []*ViolationInstance
+	workflow := &Workflow{Path: path, Content: content}
 	// Top-level object map
 	for _, docMap := range node.Content {
 		if docMap.Kind != yaml.MappingNode {
 			continue
 		}
-		// Top-level object map
-		for _, docMap := range node.Content {
-			if docMap.Kind != yaml.MappingNode {
+		workflow.Root = docMap
+
+		// jobs: keyword
+		jobs := mappingValue(docMap, "jobs")
+		if jobs == nil || jobs.Kind != yaml.MappingNode {
+			continue
+		}
+
+		for i := 0; i+1 < len(jobs.Content); i += 2 {
+			jobName, jobMap := jobs.Content[i].Value, jobs.Content[i+1]
+			if jobMap.Kind != yaml.MappingNode {
 				continue
 			}
+			job := &WorkflowJob{Name: jobName, Node: jobMap}
+			workflow.Jobs = append(workflow.Jobs, job)
 
-			for i, topLevelMap := range docMap.Content {
-				// jobs: keyword
-				if topLevelMap.Value == "jobs" {
-					jobs := docMap.Content[i+1]
-					if jobs.Kind != yaml.MappingNode {
-						continue
-					}
-
-					for _, jobMap := range jobs.Content {
-						if jobMap.Kind != yaml.MappingNode {
-							continue
-						}
-						for j, sub := range jobMap.Content {
-							// List of steps, iterate over each step and find the "uses" clause.
-							if sub.Value == "steps" {
-								steps := jobMap.Content[j+1]
-								for _, step := range steps.Content {
-									if step.Kind != yaml.MappingNode {
-										continue
-									}
-									for k, property := range step.Content {
-										if property.Value == "uses" {
-											uses := step.Content[k+1]
-											// Looking for the specific 'hashicorp/setup-terraform' action
-											if strings.HasPrefix(uses.Value, "hashicorp/setup-terraform") {
-												violations = append(violations, &ViolationInstance{ViolationType: tokenSetupTerraform, Path: path, Line: uses.Line})
-											}
-										}
-									}
-								}
-							}
-						}
-					}
+			// List of steps, record each step and its "uses" clause.
+			steps := mappingValue(jobMap, "steps")
+			if steps == nil || steps.Kind != yaml.SequenceNode {
+				continue
+			}
+			for j, step := range steps.Content {
+				if step.Kind != yaml.MappingNode {
+					continue
 				}
+				job.Steps = append(job.Steps, &WorkflowStep{
+					Job:   jobName,
+					Index: j,
+					Node:  step,
+					Uses:  mappingValue(step, "uses"),
+				})
+			}
+		}
+	}
+	return workflow, nil
+}
+
+// setupTerraformRule reports steps that use the 'hashicorp/setup-terraform'
+// action, or any of the actions configured with the "actions" option.
+type setupTerraformRule struct {
+	info *RuleInfo
+}
+
+func (r *setupTerraformRule) Info() *RuleInfo { return r.info }
+
+func (r *setupTerraformRule) CheckWorkflow(pass *Pass, workflow *Workflow) {
+	actions := pass.Option(optionActions, defaultSetupTerraformActions)
+	replacement := defaultSetupTerraformReplacement()
+	if opt := pass.Option(optionReplacement, nil); len(opt) > 0 {
+		replacement = opt[0]
+	}
+
+	for _, job := range workflow.Jobs {
+		for _, step := range job.Steps {
+			// Looking for the specific 'hashicorp/setup-terraform' action
+			if step.Uses == nil || !usesAnyAction(step.Uses.Value, actions) {
+				continue
+			}
+			s := yamlScalarSpan(step.Uses)
+			v := &ViolationInstance{
+				Line:      s.startLine,
+				Column:    s.startColumn,
+				EndLine:   s.endLine,
+				EndColumn: s.endColumn,
+				Object:    step.Object(),
 			}
+			if replacement != "" {
+				v.Fix = replaceScalarFix(workflow.Content, step.Uses, replacement)
+			}
+			pass.Report(v)
 		}
 	}
+}
 
-	return violations, nil
+// defaultSetupTerraformReplacement returns the action reference that replaces
+// setup-terraform, pinned to the release of this tool. It returns an empty
+// string for development builds, which have no release to pin to.
+func defaultSetupTerraformReplacement() string {
+	if version.Version == "" || version.Version == "unknown" {
+		return ""
+	}
+	return secureSetupTerraformAction + "@v" + strings.TrimPrefix(version.Version, "v")
+}
+
+// replaceScalarFix returns a fix replacing the value of a scalar node with
+// value, keeping any quotes around it. It returns nil if the value cannot be
+// located in content, for example because it contains escape sequences.
+func replaceScalarFix(content []byte, node *yaml.Node, value string) *Fix {
+	start := byteOffset(content, node.Line, node.Column)
+	if start < 0 {
+		return nil
+	}
+	if node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 {
+		start++
+	}
+	end := start + len(node.Value)
+	if end > len(content) || string(content[start:end]) != node.Value {
+		return nil
+	}
+	return &Fix{
+		Description: fmt.Sprintf("Use %s", value),
+		Edits:       []TextEdit{{Start: start, End: end, NewText: value}},
+	}
+}
+
+// usesAnyAction returns true if the uses value references one of the actions.
+func usesAnyAction(uses string, actions []string) bool {
+	for _, a := range actions {
+		if strings.HasPrefix(uses, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value for the given key of a mapping node, or nil if
+// the key is not present.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlScalarSpan returns the span of a single-line scalar node, including any
+// quotes around it.
+func yamlScalarSpan(node *yaml.Node) span {
+	width := len(node.Value)
+	if node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 {
+		width += 2
+	}
+	return span{
+		startLine:   node.Line,
+		startColumn: node.Column,
+		endLine:     node.Line,
+		endColumn:   node.Column + width,
+	}
 }
 
 func (tfl *GitHubActionLinter) Selectors() []string { return actionSelectors }
@@ -108,3 +308,71 @@ func parseYAML(r io.Reader) (*yaml.Node, error) {
 	}
 	return &m, nil
 }
+
+// yamlScopes are the lines of a YAML document. YAML nodes do not record where
+// they end, so the extent of mapping entries and sequence items is determined
+// by indentation.
+type yamlScopes struct {
+	lines []string
+}
+
+// scopeEnd returns the last line of the YAML mapping entry or sequence item
+// that starts at line.
+func (s *yamlScopes) scopeEnd(line int) int {
+	lines := s.lines
+	if line < 1 || line > len(lines) {
+		return line
+	}
+	start := lines[line-1]
+	indent := indentation(start)
+	isItem := strings.HasPrefix(strings.TrimSpace(start), "-")
+
+	end := line
+	for i := line; i < len(lines); i++ {
+		next := lines[i]
+		trimmed := strings.TrimSpace(next)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		nextIndent := indentation(next)
+		// Sequences are allowed to start at the same indentation as the key
+		// that owns them.
+		sameLevelItem := !isItem && nextIndent == indent && strings.HasPrefix(trimmed, "-")
+		if nextIndent <= indent && !sameLevelItem {
+			break
+		}
+		end = i + 1
+	}
+	return end
+}
+
+// enclosingScope returns the first and last line of the mapping entry or
+// sequence item that contains line, for example the step containing a "uses"
+// key.
+func (s *yamlScopes) enclosingScope(line int) (int, int) {
+	lines := s.lines
+	if line < 1 || line > len(lines) {
+		return line, line
+	}
+
+	start := line
+	if !strings.HasPrefix(strings.TrimSpace(lines[line-1]), "-") {
+		indent := indentation(lines[line-1])
+		for i := line - 1; i >= 1; i-- {
+			trimmed := strings.TrimSpace(lines[i-1])
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if indentation(lines[i-1]) < indent {
+				start = i
+				break
+			}
+		}
+	}
+	return start, s.scopeEnd(start)
+}
+
+// indentation returns the number of leading spaces on a line.
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -90,6 +90,7 @@ jobs:
 		name        string
 		filename    string
 		content     string
+		cfg         *Config
 		expectCount int
 		expect      []*ViolationInstance
 		wantError   bool
@@ -110,8 +111,70 @@ jobs:
 			expect: []*ViolationInstance{
 				{
 					ViolationType: "setup-terraform",
+					RuleID:        "SST003",
+					Severity:      SeverityError,
+					Message:       `Step uses "hashicorp/setup-terraform" directly.`,
+					Remediation:   "Use 'abcxyz/secure-setup-terraform' with the same inputs instead.",
 					Path:          "/test/myfile2",
 					Line:          31,
+					Column:        15,
+					EndLine:       31,
+					EndColumn:     83,
+					Object:        "jobs.someotherjob.steps[0]",
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:        "yaml with setup-terraform action and replacement",
+			filename:    "/test/myfile3",
+			content:     withSetupTerraform,
+			cfg:         replacementConfig,
+			expectCount: 1,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "setup-terraform",
+					RuleID:        "SST003",
+					Severity:      SeverityError,
+					Message:       `Step uses "hashicorp/setup-terraform" directly.`,
+					Remediation:   "Use 'abcxyz/secure-setup-terraform' with the same inputs instead.",
+					Path:          "/test/myfile3",
+					Line:          31,
+					Column:        15,
+					EndLine:       31,
+					EndColumn:     83,
+					Object:        "jobs.someotherjob.steps[0]",
+					Fix: &Fix{
+						Description: "Use abcxyz/secure-setup-terraform@v1",
+						Edits:       []TextEdit{{Start: 1005, End: 1071, NewText: "abcxyz/secure-setup-terraform@v1"}},
+					},
+				},
+			},
+			wantError: false,
+		},
+		{
+			name:        "non-ascii text before setup-terraform action",
+			filename:    "/test/myfile4",
+			content:     "jobs:\n  plan:\n    steps:\n      - { name: 'Ünïcödé', uses: 'hashicorp/setup-terraform@v2' }\n",
+			cfg:         replacementConfig,
+			expectCount: 1,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "setup-terraform",
+					RuleID:        "SST003",
+					Severity:      SeverityError,
+					Message:       `Step uses "hashicorp/setup-terraform" directly.`,
+					Remediation:   "Use 'abcxyz/secure-setup-terraform' with the same inputs instead.",
+					Path:          "/test/myfile4",
+					Line:          4,
+					Column:        34,
+					EndLine:       4,
+					EndColumn:     64,
+					Object:        "jobs.plan.steps[0]",
+					Fix: &Fix{
+						Description: "Use abcxyz/secure-setup-terraform@v1",
+						Edits:       []TextEdit{{Start: 63, End: 91, NewText: "abcxyz/secure-setup-terraform@v1"}},
+					},
 				},
 			},
 			wantError: false,
@@ -122,7 +185,7 @@ jobs:
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			l := GitHubActionLinter{}
+			l := GitHubActionLinter{cfg: tc.cfg}
 			results, err := l.FindViolations([]byte(tc.content), tc.filename)
 			if tc.wantError != (err != nil) {
 				t.Errorf("expected error want: %#v, got: %#v - error: %v", tc.wantError, err != nil, err)
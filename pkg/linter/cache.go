@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+// cacheVersion is incremented whenever the layout of cache entries changes.
+const cacheVersion = 2
+
+// Cache stores the results of linting a file on disk, so that unchanged files
+// are not parsed again on the next run. Entries are keyed by the file path and
+// content, the tool version, the linter and the rule configuration, so any
+// change to one of them is a cache miss. A Cache is safe for concurrent use.
+type Cache struct {
+	dir string
+}
+
+// cacheEntry is the cached outcome of linting a single file.
+type cacheEntry struct {
+	Violations   []*ViolationInstance `json:"violations"`
+	Suppressions []*Suppression       `json:"suppressions"`
+	Diagnostic   *Diagnostic          `json:"diagnostic,omitempty"`
+}
+
+// DefaultCacheDir returns the default cache directory, a directory in the
+// user's cache directory.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "secure-setup-terraform"), nil
+}
+
+// OpenCache returns a cache stored in dir, creating the directory if needed.
+func OpenCache(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Dir returns the directory the cache is stored in.
+func (c *Cache) Dir() string { return c.dir }
+
+// Clear removes every entry from the cache.
+func (c *Cache) Clear() error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(c.dir, entry.Name())); err != nil {
+			return fmt.Errorf("failed to clear cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// cacheKey returns the key of the result of linting content at path.
+func cacheKey(path, root string, content []byte, linter Linter, cfg *Config) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "v%d\x00%s\x00%s\x00%T\x00%s\x00%s\x00", cacheVersion, version.Version, version.Commit, linter, path, root)
+
+	// Registered rules and their configuration change which violations are
+	// reported.
+	for _, r := range Rules() {
+		fmt.Fprintf(h, "%s\x00", r.Info().ID)
+	}
+	if cfg != nil {
+		// Maps are encoded in key order, so the encoding is stable.
+		b, err := json.Marshal(cfg.Rules)
+		if err == nil {
+			h.Write(b)
+		}
+	}
+	if m, ok := linter.(moduleLinter); ok {
+		fmt.Fprintf(h, "\x00%s", m.moduleKey(path))
+	}
+	h.Write([]byte{0})
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// entryPath returns the path of the entry with the given key. Entries are
+// spread over subdirectories to keep directories small.
+func (c *Cache) entryPath(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns the cached entry for key, or nil on a miss. Unreadable entries
+// are treated as misses.
+func (c *Cache) get(key string) *cacheEntry {
+	b, err := os.ReadFile(c.entryPath(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// put stores entry under key. Failures are ignored, the cache only speeds up
+// later runs. Entries are written to a temporary file and renamed so that
+// concurrent runs never read a partial entry.
+func (c *Cache) put(key string, entry *cacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	path := c.entryPath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, werr := f.Write(b)
+	cerr := f.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(f.Name())
+		return
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun_Cache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.tf":   testLocalExec,
+		"other.tf":  "variable \"a\" {}\n",
+		"broken.tf": "a = ~b\n",
+	})
+	cache, err := OpenCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(cfg *Config) *Result {
+		t.Helper()
+		res, err := Run(context.Background(), &Options{
+			Paths:   []string{dir},
+			Linters: []Linter{&TerraformLinter{}},
+			Config:  cfg,
+			Cache:   cache,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	first := run(nil)
+	if got := first.Stats.CacheHits; got != 0 {
+		t.Errorf("expected no cache hits on the first run, got %d", got)
+	}
+
+	second := run(nil)
+	if got, want := second.Stats.CacheHits, 3; got != want {
+		t.Errorf("expected %d cache hits, got %d", want, got)
+	}
+	if diff := cmp.Diff(first.Violations, second.Violations); diff != "" {
+		t.Errorf("cached violations (-want,+got):\n%s", diff)
+	}
+	if diff := cmp.Diff(first.Diagnostics, second.Diagnostics); diff != "" {
+		t.Errorf("cached diagnostics (-want,+got):\n%s", diff)
+	}
+
+	// Changing the rule configuration invalidates the cache.
+	cfg := &Config{Rules: map[string]*RuleConfig{ruleIDLocalExec: {Severity: SeverityWarning}}}
+	third := run(cfg)
+	if got := third.Stats.CacheHits; got != 0 {
+		t.Errorf("expected no cache hits after a config change, got %d", got)
+	}
+	for _, v := range third.Violations {
+		if got := v.Severity; v.RuleID == ruleIDLocalExec && got != SeverityWarning {
+			t.Errorf("expected severity %q, got %q", SeverityWarning, got)
+		}
+	}
+
+	// Declaring a provider in another file of the module can change the
+	// violations of every file in it.
+	writeTestFiles(t, dir, map[string]string{
+		"versions.tf": "terraform {\n  required_providers {\n    aws = { source = \"hashicorp/aws\" }\n  }\n}\n",
+	})
+	if got := run(cfg).Stats.CacheHits; got != 0 {
+		t.Errorf("expected no cache hits after a provider was declared, got %d", got)
+	}
+
+	if err := cache.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if got := run(nil).Stats.CacheHits; got != 0 {
+		t.Errorf("expected no cache hits after clearing, got %d", got)
+	}
+}
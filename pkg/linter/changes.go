@@ -0,0 +1,208 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkHeaderPattern matches the header of a hunk in a unified diff and
+// captures the start and length of the new side.
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// changeSet is the set of files and lines changed since a git ref.
+type changeSet struct {
+	// root is the top-level directory of the git work tree, with symlinks
+	// resolved.
+	root string
+
+	// lines maps slash-separated paths relative to root to their added or
+	// modified lines. A nil map means every line is new, for example for
+	// untracked files.
+	lines map[string]map[int]bool
+}
+
+// gitChanges returns the files and lines in the work tree containing dir that
+// changed since the merge base of ref and HEAD. Uncommitted and untracked
+// files are included. Only the local repository is read.
+func gitChanges(ctx context.Context, dir, ref string) (*changeSet, error) {
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	top, err := runGit(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.EvalSymlinks(strings.TrimSpace(string(top)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve git work tree: %w", err)
+	}
+
+	base, err := runGit(ctx, root, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := runGit(ctx, root, "-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--unified=0", strings.TrimSpace(string(base)), "--")
+	if err != nil {
+		return nil, err
+	}
+	changes, err := parseUnifiedDiff(diff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse git diff: %w", err)
+	}
+	changes.root = root
+
+	untracked, err := runGit(ctx, root, "-c", "core.quotePath=false", "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range strings.Split(string(untracked), "\n") {
+		if name != "" {
+			changes.lines[name] = nil
+		}
+	}
+	return changes, nil
+}
+
+// runGit runs git in dir and returns its standard output.
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), msg)
+		}
+		return nil, fmt.Errorf("git %s: %w", strings.Join(args, " "), err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseUnifiedDiff returns the added and modified lines of each file in a
+// unified diff produced with --unified=0. Deleted files are omitted.
+func parseUnifiedDiff(diff []byte) (*changeSet, error) {
+	changes := &changeSet{lines: make(map[string]map[int]bool)}
+
+	var current map[int]bool
+	scanner := bufio.NewScanner(bytes.NewReader(diff))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			name := strings.TrimPrefix(line, "+++ ")
+			if name == "/dev/null" {
+				current = nil
+				continue
+			}
+			name = strings.TrimPrefix(name, "b/")
+			current = make(map[int]bool)
+			changes.lines[name] = current
+		case strings.HasPrefix(line, "@@ "):
+			m := hunkHeaderPattern.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header %q", line)
+			}
+			if current == nil {
+				continue
+			}
+			start, err := strconv.Atoi(m[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid hunk header %q: %w", line, err)
+			}
+			count := 1
+			if m[2] != "" {
+				if count, err = strconv.Atoi(m[2]); err != nil {
+					return nil, fmt.Errorf("invalid hunk header %q: %w", line, err)
+				}
+			}
+			for i := start; i < start+count; i++ {
+				current[i] = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// lookup returns the changed lines of the file at path, and whether the file
+// changed at all.
+func (c *changeSet) lookup(path string) (map[int]bool, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, false
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(c.root, abs)
+	if err != nil {
+		return nil, false
+	}
+	lines, ok := c.lines[filepath.ToSlash(rel)]
+	return lines, ok
+}
+
+// changed returns true if the file at path changed.
+func (c *changeSet) changed(path string) bool {
+	_, ok := c.lookup(path)
+	return ok
+}
+
+// touches returns true if any line of the violation was added or modified.
+func (c *changeSet) touches(v *ViolationInstance) bool {
+	lines, ok := c.lookup(v.Path)
+	if !ok {
+		return false
+	}
+	if lines == nil {
+		return true
+	}
+	end := v.EndLine
+	if end < v.Line {
+		end = v.Line
+	}
+	for i := v.Line; i <= end; i++ {
+		if lines[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the violations that touch a changed line.
+func (c *changeSet) filter(violations []*ViolationInstance) []*ViolationInstance {
+	kept := violations[:0]
+	for _, v := range violations {
+		if c.touches(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	diff := `diff --git a/main.tf b/main.tf
+index 1111111..2222222 100644
+--- a/main.tf
++++ b/main.tf
+@@ -2,0 +3,2 @@ resource "null_resource" "echo" {
++  provisioner "local-exec" {
++    command = "echo hello"
+@@ -10 +12 @@ resource "null_resource" "echo" {
+-  old = true
++  new = true
+@@ -20,3 +22,0 @@
+-removed
+-removed
+-removed
+diff --git a/gone.tf b/gone.tf
+deleted file mode 100644
+--- a/gone.tf
++++ /dev/null
+@@ -1,2 +0,0 @@
+-a = 1
+-b = 2
+diff --git a/new.tf b/modules/new.tf
+new file mode 100644
+--- /dev/null
++++ b/modules/new.tf
+@@ -0,0 +1 @@
++a = 1
+`
+
+	got, err := parseUnifiedDiff([]byte(diff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[int]bool{
+		"main.tf":        {3: true, 4: true, 12: true},
+		"modules/new.tf": {1: true},
+	}
+	if diff := cmp.Diff(want, got.lines); diff != "" {
+		t.Errorf("lines (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRun_Since(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %q: %v\n%s", args, err, out)
+		}
+	}
+
+	writeTestFiles(t, dir, map[string]string{
+		"old.tf":  testLocalExec,
+		"main.tf": testLocalExec,
+	})
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "initial")
+
+	// Add a second provisioner to main.tf, and an untracked file.
+	writeTestFiles(t, dir, map[string]string{
+		"main.tf": testLocalExec + `resource "null_resource" "other" {
+  provisioner "remote-exec" {
+    inline = ["true"]
+  }
+}
+`,
+		"new.tf": testLocalExec,
+	})
+
+	cases := []struct {
+		name      string
+		wholeFile bool
+		expect    []string
+	}{
+		{
+			name:   "changed lines",
+			expect: []string{"main.tf:6", "main.tf:7", "new.tf:1", "new.tf:1", "new.tf:2"},
+		},
+		{
+			name:      "whole file",
+			wholeFile: true,
+			expect:    []string{"main.tf:1", "main.tf:1", "main.tf:2", "main.tf:6", "main.tf:7", "new.tf:1", "new.tf:1", "new.tf:2"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := Run(context.Background(), &Options{
+				Paths:          []string{dir},
+				Since:          "HEAD",
+				SinceWholeFile: tc.wholeFile,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, v := range res.Violations {
+				rel, err := filepath.Rel(dir, v.Path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel)+":"+strconv.Itoa(v.Line))
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("violations (-want,+got):\n%s", diff)
+			}
+			if got, want := len(res.Files), 2; got != want {
+				t.Errorf("expected %d files, got %d: %q", want, got, res.Files)
+			}
+		})
+	}
+}
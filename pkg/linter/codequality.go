@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// codeQualityParseError is the check name of issues for files that could not
+// be linted.
+const codeQualityParseError = "parse-error"
+
+// codeQualityIssue is a single issue of a GitLab Code Quality report. See
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#code-quality-report-format.
+type codeQualityIssue struct {
+	Description string               `json:"description"`
+	CheckName   string               `json:"check_name"`
+	Fingerprint string               `json:"fingerprint"`
+	Severity    string               `json:"severity"`
+	Location    *codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string            `json:"path"`
+	Lines *codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// writeCodeQuality writes the result to w as a GitLab Code Quality report.
+// Baselined violations are reported as warnings and files that could not be
+// linted are reported as critical issues.
+func writeCodeQuality(w io.Writer, res *Result) error {
+	issues := make([]*codeQualityIssue, 0, len(res.Violations)+len(res.Diagnostics))
+
+	// GitLab requires fingerprints to be unique within a report, but violations
+	// of the same rule in identical blocks share a baseline fingerprint, so the
+	// number of earlier occurrences is mixed in.
+	occurrences := make(map[string]int)
+	add := func(issue *codeQualityIssue, seed string) {
+		n := occurrences[seed]
+		occurrences[seed]++
+		h := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d", seed, n))
+		issue.Fingerprint = hex.EncodeToString(h[:])
+		issues = append(issues, issue)
+	}
+
+	for _, v := range res.Violations {
+		check := v.RuleID
+		if check == "" {
+			check = v.ViolationType
+		}
+		desc := violationMessage(v)
+		if v.Object != "" {
+			desc += " (in " + v.Object + ")"
+		}
+		severity := v.Severity
+		if v.Baselined {
+			severity = SeverityWarning
+		}
+		line := v.Line
+		if line < 1 {
+			line = 1
+		}
+		seed := v.Fingerprint
+		if seed == "" {
+			seed = fmt.Sprintf("%s\x00%s\x00%d", check, v.Path, v.Line)
+		}
+		add(&codeQualityIssue{
+			Description: desc,
+			CheckName:   check,
+			Severity:    codeQualitySeverity(severity),
+			Location: &codeQualityLocation{
+				Path:  codeQualityPath(v.Path),
+				Lines: &codeQualityLines{Begin: line, End: v.EndLine},
+			},
+		}, seed)
+	}
+
+	for _, d := range res.Diagnostics {
+		line := d.Line
+		if line < 1 {
+			line = 1
+		}
+		add(&codeQualityIssue{
+			Description: d.Message,
+			CheckName:   codeQualityParseError,
+			Severity:    "critical",
+			Location: &codeQualityLocation{
+				Path:  codeQualityPath(d.Path),
+				Lines: &codeQualityLines{Begin: line},
+			},
+		}, fmt.Sprintf("%s\x00%s\x00%s", codeQualityParseError, d.Path, d.Message))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(issues); err != nil {
+		return fmt.Errorf("failed to encode code quality report: %w", err)
+	}
+	return nil
+}
+
+// codeQualitySeverity converts a Severity to a Code Quality severity.
+func codeQualitySeverity(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "minor"
+	case SeverityInfo:
+		return "info"
+	default:
+		return "major"
+	}
+}
+
+// codeQualityPath returns path cleaned and with forward slashes, since GitLab
+// matches paths against the repository root.
+func codeQualityPath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWriteCodeQuality(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		res    *Result
+		expect []*codeQualityIssue
+	}{
+		{
+			name:   "no violations",
+			res:    &Result{},
+			expect: []*codeQualityIssue{},
+		},
+		{
+			name: "violations and diagnostics",
+			res: &Result{
+				Violations: []*ViolationInstance{
+					newViolation("local-exec", "./modules/main.tf", span{3, 15, 3, 27}, "null_resource.echo"),
+					{ViolationType: "setup-terraform", RuleID: "SST003", Severity: SeverityWarning, Path: ".github/workflows/ci.yml", Line: 31},
+					{ViolationType: "remote-exec", RuleID: "SST002", Severity: SeverityError, Path: "main.tf", Line: 9, Baselined: true},
+					{ViolationType: "custom", Severity: SeverityInfo, Path: "main.tf"},
+				},
+				Diagnostics: []*Diagnostic{
+					{Path: "broken.tf", Line: 2, Column: 1, Message: "Argument or block definition required"},
+				},
+			},
+			expect: []*codeQualityIssue{
+				{
+					Description: `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform. (in null_resource.echo)`,
+					CheckName:   "SST001",
+					Severity:    "major",
+					Location:    &codeQualityLocation{Path: "modules/main.tf", Lines: &codeQualityLines{Begin: 3, End: 3}},
+				},
+				{
+					Description: `"setup-terraform" detected`,
+					CheckName:   "SST003",
+					Severity:    "minor",
+					Location:    &codeQualityLocation{Path: ".github/workflows/ci.yml", Lines: &codeQualityLines{Begin: 31}},
+				},
+				{
+					Description: `"remote-exec" detected`,
+					CheckName:   "SST002",
+					Severity:    "minor",
+					Location:    &codeQualityLocation{Path: "main.tf", Lines: &codeQualityLines{Begin: 9}},
+				},
+				{
+					Description: `"custom" detected`,
+					CheckName:   "custom",
+					Severity:    "info",
+					Location:    &codeQualityLocation{Path: "main.tf", Lines: &codeQualityLines{Begin: 1}},
+				},
+				{
+					Description: "Argument or block definition required",
+					CheckName:   "parse-error",
+					Severity:    "critical",
+					Location:    &codeQualityLocation{Path: "broken.tf", Lines: &codeQualityLines{Begin: 2}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := writeCodeQuality(&b, tc.res); err != nil {
+				t.Fatal(err)
+			}
+			var got []*codeQualityIssue
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode report: %v\n%s", err, b.String())
+			}
+			if diff := cmp.Diff(tc.expect, got, cmpopts.IgnoreFields(codeQualityIssue{}, "Fingerprint")); diff != "" {
+				t.Errorf("issues (-want,+got):\n%s", diff)
+			}
+			for _, issue := range got {
+				if issue.Fingerprint == "" {
+					t.Errorf("issue %q has no fingerprint", issue.Description)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCodeQuality_UniqueFingerprints(t *testing.T) {
+	t.Parallel()
+
+	// Identical blocks share a baseline fingerprint.
+	res := &Result{
+		Violations: []*ViolationInstance{
+			{ViolationType: "local-exec", Path: "main.tf", Line: 3, Fingerprint: "abc"},
+			{ViolationType: "local-exec", Path: "main.tf", Line: 9, Fingerprint: "abc"},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := writeCodeQuality(&b, res); err != nil {
+		t.Fatal(err)
+	}
+	var got []*codeQualityIssue
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Fingerprint == got[1].Fingerprint {
+		t.Errorf("expected 2 issues with distinct fingerprints, got %#v", got)
+	}
+}
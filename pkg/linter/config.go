@@ -0,0 +1,312 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileName is the name of the project configuration file.
+const ConfigFileName = ".secure-setup-terraform.yaml"
+
+// unknownFieldPattern matches the yaml.v3 error for an unknown key.
+var unknownFieldPattern = regexp.MustCompile(`field (\S+) not found in type \S+`)
+
+// configVersion is the only supported configuration file version.
+const configVersion = 1
+
+// Config is the project configuration, loaded from a ConfigFileName file.
+type Config struct {
+	// Version is the version of the configuration file format. It must be 1.
+	Version int `yaml:"version"`
+
+	// Include are globs of files to lint. When empty, every file matching a
+	// linter's selectors is linted. Files outside the directory of the
+	// configuration are always linted.
+	Include []string `yaml:"include"`
+
+	// Exclude are globs of files and directories to skip. Files outside the
+	// directory of the configuration are never skipped.
+	Exclude []string `yaml:"exclude"`
+
+	// Rules configures individual rules, keyed by rule name or ID.
+	Rules map[string]*RuleConfig `yaml:"rules"`
+
+	// dir is the directory containing the configuration file. Include and
+	// exclude globs are relative to it.
+	dir string
+}
+
+// RuleConfig configures a single rule.
+type RuleConfig struct {
+	// Enabled turns the rule on or off. Rules are enabled by default.
+	Enabled *bool `yaml:"enabled"`
+
+	// Severity overrides the default severity of the rule.
+	Severity Severity `yaml:"severity"`
+
+	// Options are rule specific options.
+	Options map[string][]string `yaml:"options"`
+}
+
+// FindConfig looks for a ConfigFileName file in the directory of start and
+// each of its parents. It returns an empty string if no file is found.
+func FindConfig(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %q: %w", start, err)
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+
+	for {
+		candidate := filepath.Join(dir, ConfigFileName)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to check for config at %q: %w", candidate, err)
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// ResolveConfig loads the configuration file at path. If path is empty, the
+// nearest configuration file above target is used instead. It returns nil if
+// no configuration file is found.
+func ResolveConfig(path, target string) (*Config, error) {
+	if path == "" {
+		found, err := FindConfig(target)
+		if err != nil {
+			return nil, err
+		}
+		if found == "" {
+			return nil, nil
+		}
+		path = found
+	}
+	return LoadConfig(path)
+}
+
+// LoadConfig reads and validates the configuration file at path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg, err := parseConfig(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %q: %w", path, err)
+	}
+	cfg.dir = filepath.Dir(abs)
+	return cfg, nil
+}
+
+// LoadConfigFS reads and validates the configuration file at name within fsys.
+// Include and exclude globs are relative to the root of fsys.
+func LoadConfigFS(fsys fs.FS, name string) (*Config, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	cfg, err := parseConfig(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", name, err)
+	}
+	return cfg, nil
+}
+
+// parseConfig decodes and validates a configuration file. Unknown keys are
+// rejected.
+func parseConfig(b []byte) (*Config, error) {
+	var cfg Config
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			// yaml.v3 reports unknown keys in terms of Go types, rewrite them in
+			// terms of the file.
+			msgs := make([]error, 0, len(typeErr.Errors))
+			for _, msg := range typeErr.Errors {
+				msgs = append(msgs, errors.New(unknownFieldPattern.ReplaceAllString(msg, `unknown key "$1"`)))
+			}
+			return nil, errors.Join(msgs...)
+		}
+		return nil, fmt.Errorf("failed to decode: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// validate checks the configuration for unknown rules, options and invalid
+// values. Rule keys are normalized to rule IDs.
+func (c *Config) validate() error {
+	var merr error
+	if c.Version != configVersion {
+		merr = errors.Join(merr, fmt.Errorf("version: must be %d, got %d", configVersion, c.Version))
+	}
+	for _, g := range c.Include {
+		if err := validateGlob(g); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("include: %w", err))
+		}
+	}
+	for _, g := range c.Exclude {
+		if err := validateGlob(g); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("exclude: %w", err))
+		}
+	}
+
+	rules := make(map[string]*RuleConfig, len(c.Rules))
+	keys := make([]string, 0, len(c.Rules))
+	for k := range c.Rules {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		rc := c.Rules[key]
+		rule := lookupRule(key)
+		if rule == nil {
+			merr = errors.Join(merr, fmt.Errorf("rules: unknown rule %q, must be one of %q", key, ruleNames()))
+			continue
+		}
+		if _, ok := rules[rule.ID]; ok {
+			merr = errors.Join(merr, fmt.Errorf("rules: rule %q is configured more than once", key))
+			continue
+		}
+		if rc == nil {
+			rc = &RuleConfig{}
+		}
+		if rc.Severity != "" && !validSeverity(rc.Severity) {
+			merr = errors.Join(merr, fmt.Errorf("rules.%s.severity: must be one of %q, got %q", key, Severities, rc.Severity))
+		}
+		for opt := range rc.Options {
+			if _, ok := rule.Options[opt]; !ok {
+				merr = errors.Join(merr, fmt.Errorf("rules.%s.options: unknown option %q, must be one of %q", key, opt, sortedKeys(rule.Options)))
+			}
+		}
+		rules[rule.ID] = rc
+	}
+	c.Rules = rules
+	return merr
+}
+
+// ruleConfig returns the configuration for the rule with the given ID, or nil.
+func (c *Config) ruleConfig(id string) *RuleConfig {
+	if c == nil {
+		return nil
+	}
+	return c.Rules[id]
+}
+
+// ruleEnabled returns true if the rule with the given ID is enabled.
+func (c *Config) ruleEnabled(id string) bool {
+	rc := c.ruleConfig(id)
+	return rc == nil || rc.Enabled == nil || *rc.Enabled
+}
+
+// ruleOption returns the value of a rule option, or def if it is not set.
+func (c *Config) ruleOption(id, name string, def []string) []string {
+	rc := c.ruleConfig(id)
+	if rc == nil {
+		return def
+	}
+	if v, ok := rc.Options[name]; ok {
+		return v
+	}
+	return def
+}
+
+// apply removes violations of disabled rules and applies severity overrides.
+func (c *Config) apply(violations []*ViolationInstance) []*ViolationInstance {
+	if c == nil {
+		return violations
+	}
+	kept := violations[:0]
+	for _, v := range violations {
+		if !c.ruleEnabled(v.RuleID) {
+			continue
+		}
+		if rc := c.ruleConfig(v.RuleID); rc != nil && rc.Severity != "" {
+			v.Severity = rc.Severity
+		}
+		kept = append(kept, v)
+	}
+	return kept
+}
+
+// relPath returns path relative to the configuration directory using forward
+// slashes, or false if path is outside the configuration directory.
+func (c *Config) relPath(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(c.dir, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// excludes returns true if the file or directory at rel, relative to the
+// configuration directory, matches an exclude glob.
+func (c *Config) excludes(rel string) bool {
+	return c != nil && matchAnyGlob(c.Exclude, rel)
+}
+
+// includes returns true if the file at rel, relative to the configuration
+// directory, matches an include glob, or there are no include globs.
+func (c *Config) includes(rel string) bool {
+	return c == nil || len(c.Include) == 0 || matchAnyGlob(c.Include, rel)
+}
+
+// configurable is implemented by linters that read rule options from the
+// project configuration.
+type configurable interface {
+	configure(cfg *Config)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,215 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		content   string
+		wantError string
+	}{
+		{
+			name: "valid",
+			content: `
+version: 1
+include:
+  - '**/*.tf'
+exclude:
+  - 'examples/**'
+rules:
+  local-exec:
+    enabled: false
+  SST002:
+    severity: 'warning'
+  setup-terraform:
+    options:
+      actions:
+        - 'hashicorp/setup-terraform'
+        - 'example/setup-terraform'
+`,
+		},
+		{
+			name:      "missing version",
+			content:   `rules: {}`,
+			wantError: "version: must be 1, got 0",
+		},
+		{
+			name: "unknown key",
+			content: `
+version: 1
+exclusions: ['examples/**']
+`,
+			wantError: `line 3: unknown key "exclusions"`,
+		},
+		{
+			name: "unknown rule key",
+			content: `
+version: 1
+rules:
+  local-exec:
+    enable: false
+`,
+			wantError: `line 5: unknown key "enable"`,
+		},
+		{
+			name: "unknown rule",
+			content: `
+version: 1
+rules:
+  curl-bash: {}
+`,
+			wantError: `unknown rule "curl-bash"`,
+		},
+		{
+			name: "duplicate rule",
+			content: `
+version: 1
+rules:
+  local-exec: {}
+  SST001: {}
+`,
+			wantError: "configured more than once",
+		},
+		{
+			name: "invalid severity",
+			content: `
+version: 1
+rules:
+  local-exec:
+    severity: 'fatal'
+`,
+			wantError: `rules.local-exec.severity: must be one of`,
+		},
+		{
+			name: "unknown option",
+			content: `
+version: 1
+rules:
+  local-exec:
+    options:
+      commands: ['echo']
+`,
+			wantError: `rules.local-exec.options: unknown option "commands"`,
+		},
+		{
+			name: "invalid glob",
+			content: `
+version: 1
+exclude: ['[']
+`,
+			wantError: "exclude: invalid glob",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := parseConfig([]byte(tc.content))
+			if tc.wantError == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Errorf("expected error containing %q, got %v", tc.wantError, err)
+			}
+		})
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	nested := filepath.Join(root, "modules", "network")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(root, ConfigFileName)
+	if err := os.WriteFile(want, []byte("version: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(nested, "main.tf")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, start := range []string{root, nested, file} {
+		got, err := FindConfig(start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("FindConfig(%q) = %q, want %q", start, got, want)
+		}
+	}
+}
+
+func TestConfig_Apply(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]byte(`
+version: 1
+rules:
+  local-exec:
+    enabled: false
+  remote-exec:
+    severity: 'info'
+  setup-terraform:
+    options:
+      actions: ['example/setup-terraform']
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations := cfg.apply([]*ViolationInstance{
+		newViolation(tokenLocalExec, "main.tf", span{}, ""),
+		newViolation(tokenRemoteExec, "main.tf", span{}, ""),
+	})
+	if len(violations) != 1 || violations[0].ViolationType != tokenRemoteExec {
+		t.Fatalf("expected only the remote-exec violation, got %v", violations)
+	}
+	if got := violations[0].Severity; got != SeverityInfo {
+		t.Errorf("expected severity %q, got %q", SeverityInfo, got)
+	}
+
+	l := &GitHubActionLinter{}
+	l.configure(cfg)
+	results, err := l.FindViolations([]byte(`
+jobs:
+  build:
+    steps:
+      - uses: 'hashicorp/setup-terraform@v3'
+      - uses: 'example/setup-terraform@v1'
+`), "ci.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Line != 6 {
+		t.Errorf("expected a single violation on line 6, got %v", results)
+	}
+}
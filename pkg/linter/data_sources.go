@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// optionHosts is the option of the http-data-source rule listing the hosts
+// data sources may send requests to.
+const optionHosts = "hosts"
+
+// urlSchema selects the url argument of an http data source.
+var urlSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "url"}},
+}
+
+// isDataSource returns true if b is a data source of the given type, either at
+// the top level or scoped to a check block.
+func (b *Block) isDataSource(typ string) bool {
+	return b.Type == "data" && len(b.Labels) == 2 && b.Labels[0] == typ &&
+		(b.Parent == nil || b.Parent.Type == "check")
+}
+
+// externalDataSourceRule reports external data sources, which run a program on
+// the machine running Terraform every time the configuration is planned.
+type externalDataSourceRule struct {
+	info *RuleInfo
+}
+
+func (r *externalDataSourceRule) Info() *RuleInfo { return r.info }
+
+// CheckTerraform looks for data "external" blocks.
+func (r *externalDataSourceRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	file.Inspect(func(b *Block) bool {
+		if b.isDataSource("external") {
+			pass.report(hclSpan(b.reportRange()), b.Address())
+		}
+		return true
+	})
+}
+
+// httpDataSourceRule reports http data sources that send requests to hosts
+// that are not allowed. Every http data source is reported unless hosts are
+// configured.
+type httpDataSourceRule struct {
+	info *RuleInfo
+}
+
+func (r *httpDataSourceRule) Info() *RuleInfo { return r.info }
+
+// CheckTerraform looks for data "http" blocks and checks the host of their
+// url. URLs that depend on variables cannot be checked and are reported.
+func (r *httpDataSourceRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	hosts := pass.Option(optionHosts, nil)
+	file.Inspect(func(b *Block) bool {
+		if !b.isDataSource("http") {
+			return true
+		}
+
+		rng := b.reportRange()
+		msg := `Data source "http" sends a request to a URL that cannot be checked against the allowed hosts.`
+		content, _, _ := b.Body.PartialContent(urlSchema)
+		if attr, ok := content.Attributes["url"]; ok {
+			rng = attr.Expr.Range()
+			if raw, ok := exprString(attr.Expr); ok {
+				host := urlHost(raw)
+				if host != "" && matchAnyHost(hosts, host) {
+					return true
+				}
+				if host != "" {
+					msg = fmt.Sprintf(`Data source "http" sends a request to host %q, which is not allowed.`, host)
+				}
+			}
+		}
+
+		s := hclSpan(rng)
+		pass.Report(&ViolationInstance{
+			Line:      s.startLine,
+			Column:    s.startColumn,
+			EndLine:   s.endLine,
+			EndColumn: s.endColumn,
+			Object:    b.Address(),
+			Message:   msg,
+		})
+		return true
+	})
+}
+
+// urlHost returns the lower case host name of raw, or an empty string if raw
+// is not an absolute URL.
+func urlHost(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// matchAnyHost returns true if host matches one of patterns. Patterns may use
+// * wildcards, for example "*.example.com".
+func matchAnyHost(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(strings.TrimSpace(pattern)), host); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
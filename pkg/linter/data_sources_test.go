@@ -0,0 +1,142 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDataSourceRules(t *testing.T) {
+	t.Parallel()
+
+	httpContent := `data "http" "github" {
+  url = "https://api.github.com/meta"
+}
+
+data "http" "internal" {
+  url = "https://Status.Example.com/health"
+}
+
+data "http" "dynamic" {
+  url = "https://${var.host}/health"
+}
+
+data "http" "missing" {}
+`
+	hosts := &Config{Rules: map[string]*RuleConfig{
+		ruleIDHTTPData: {Options: map[string][]string{optionHosts: {"api.github.com", "*.example.com"}}},
+	}}
+
+	cases := []struct {
+		name     string
+		rule     string
+		filename string
+		content  string
+		cfg      *Config
+		expect   []string
+	}{
+		{
+			name:     "external native syntax",
+			rule:     ruleIDExternalData,
+			filename: "main.tf",
+			content: `data "external" "script" {
+  program = ["./script.sh"]
+}
+
+check "health" {
+  data "external" "probe" {
+    program = ["./probe.sh"]
+  }
+}
+
+resource "external" "not_a_data_source" {}
+`,
+			expect: []string{
+				`1:6 data.external.script: Data source "external" runs a program on the machine running Terraform, even during terraform plan.`,
+				`6:8 check.health: Data source "external" runs a program on the machine running Terraform, even during terraform plan.`,
+			},
+		},
+		{
+			name:     "external json syntax",
+			rule:     ruleIDExternalData,
+			filename: "main.tf.json",
+			content: `{
+  "data": {
+    "external": {
+      "script": {"program": ["./script.sh"]}
+    }
+  }
+}
+`,
+			expect: []string{
+				`4:17 data.external.script: Data source "external" runs a program on the machine running Terraform, even during terraform plan.`,
+			},
+		},
+		{
+			name:     "http no allowed hosts",
+			rule:     ruleIDHTTPData,
+			filename: "main.tf",
+			content:  httpContent,
+			expect: []string{
+				`2:9 data.http.github: Data source "http" sends a request to host "api.github.com", which is not allowed.`,
+				`6:9 data.http.internal: Data source "http" sends a request to host "status.example.com", which is not allowed.`,
+				`10:9 data.http.dynamic: Data source "http" sends a request to a URL that cannot be checked against the allowed hosts.`,
+				`13:6 data.http.missing: Data source "http" sends a request to a URL that cannot be checked against the allowed hosts.`,
+			},
+		},
+		{
+			name:     "http allowed hosts",
+			rule:     ruleIDHTTPData,
+			filename: "main.tf",
+			content:  httpContent,
+			cfg:      hosts,
+			expect: []string{
+				`10:9 data.http.dynamic: Data source "http" sends a request to a URL that cannot be checked against the allowed hosts.`,
+				`13:6 data.http.missing: Data source "http" sends a request to a URL that cannot be checked against the allowed hosts.`,
+			},
+		},
+		{
+			name:     "http json syntax",
+			rule:     ruleIDHTTPData,
+			filename: "main.tf.json",
+			content: `{
+  "data": {
+    "http": {
+      "github": {"url": "https://api.github.com/meta"},
+      "other": {"url": "https://evil.example.net/payload"}
+    }
+  }
+}
+`,
+			cfg: hosts,
+			expect: []string{
+				`5:24 data.http.other: Data source "http" sends a request to host "evil.example.net", which is not allowed.`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := findRuleViolations(t, tc.rule, tc.filename, tc.content, tc.cfg)
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("violations (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// Diagnostic is a problem that prevented a file from being linted, such as a
+// syntax error or a failure to read it. Diagnostics are collected alongside
+// violations so that one bad file does not hide findings in the others.
+type Diagnostic struct {
+	// Path is the path of the file or directory.
+	Path string
+
+	// Line and Column are the 1-based location of the problem, or 0 if the
+	// location is unknown.
+	Line   int
+	Column int
+
+	// Message describes the problem.
+	Message string
+}
+
+// Error implements error so linters can return a Diagnostic from
+// FindViolations to report the location of a parse error.
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s: %s", diagnosticLocation(d), d.Message)
+}
+
+// diagnosticLocation formats the location of a diagnostic as
+// path[:line[:column]].
+func diagnosticLocation(d *Diagnostic) string {
+	switch {
+	case d.Line > 0 && d.Column > 0:
+		return fmt.Sprintf("%s:%d:%d", d.Path, d.Line, d.Column)
+	case d.Line > 0:
+		return fmt.Sprintf("%s:%d", d.Path, d.Line)
+	default:
+		return d.Path
+	}
+}
+
+// toDiagnostic converts an error for path into a Diagnostic, preserving the
+// location if err already is or wraps one.
+func toDiagnostic(path string, err error) *Diagnostic {
+	var d *Diagnostic
+	if errors.As(err, &d) {
+		if d.Path == "" {
+			d.Path = path
+		}
+		return d
+	}
+	return &Diagnostic{Path: path, Message: err.Error()}
+}
+
+// hclDiagnostic converts the first error in diags into a Diagnostic.
+func hclDiagnostic(path string, diags hcl.Diagnostics) *Diagnostic {
+	d := &Diagnostic{Path: path, Message: diags.Error()}
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		d.Message = diag.Summary
+		if diag.Detail != "" {
+			d.Message += ": " + diag.Detail
+		}
+		if diag.Subject != nil {
+			d.Line = diag.Subject.Start.Line
+			d.Column = diag.Subject.Start.Column
+		}
+		break
+	}
+	return d
+}
+
+// yamlLinePattern extracts the line number from yaml.v3 error messages, for
+// example "yaml: line 3: did not find expected key".
+var yamlLinePattern = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlDiagnostic converts a yaml.v3 decoding error into a Diagnostic.
+func yamlDiagnostic(path string, err error) *Diagnostic {
+	d := &Diagnostic{Path: path, Message: err.Error()}
+	if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+		if line, convErr := strconv.Atoi(m[1]); convErr == nil {
+			d.Line = line
+			d.Message = m[2]
+		}
+	}
+	return d
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// diffContext is the number of unchanged lines shown around each change in a
+// diff.
+const diffContext = 3
+
+// TextEdit replaces the bytes [Start, End) of the original file content with
+// NewText. An edit with Start == End inserts NewText.
+type TextEdit struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	NewText string `json:"new_text"`
+}
+
+// Fix is a mechanical fix for a violation. Its edits are applied together
+// against the original content of the file.
+type Fix struct {
+	// Description is a short description of the fix, for example "Use
+	// abcxyz/secure-setup-terraform".
+	Description string `json:"description"`
+
+	// Edits are the text edits that make up the fix. They must not overlap.
+	Edits []TextEdit `json:"edits"`
+}
+
+// ApplyFixes applies the fixes of violations to content, the original content
+// of the file the violations were found in. Violations without a fix are
+// ignored. All edits are made against the original bytes, so fixes do not
+// disturb surrounding formatting or comments. If any two edits overlap no fix
+// is applied and an error is returned.
+func ApplyFixes(content []byte, violations []*ViolationInstance) ([]byte, error) {
+	type ownedEdit struct {
+		TextEdit
+		owner *ViolationInstance
+	}
+	var edits []ownedEdit
+	for _, v := range violations {
+		if v.Fix == nil {
+			continue
+		}
+		for _, e := range v.Fix.Edits {
+			if e.Start < 0 || e.End < e.Start || e.End > len(content) {
+				return nil, fmt.Errorf("fix for %s at line %d has an invalid edit [%d, %d)", v.RuleID, v.Line, e.Start, e.End)
+			}
+			edits = append(edits, ownedEdit{TextEdit: e, owner: v})
+		}
+	}
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].Start != edits[j].Start {
+			return edits[i].Start < edits[j].Start
+		}
+		return edits[i].End < edits[j].End
+	})
+
+	var b bytes.Buffer
+	last := 0
+	for i, e := range edits {
+		// Two insertions at the same offset are also ambiguous, the order of
+		// the inserted text is not defined.
+		if i > 0 && (e.Start < edits[i-1].End || e.Start == edits[i-1].Start) {
+			prev := edits[i-1].owner
+			return nil, fmt.Errorf("overlapping fixes for %s at line %d and %s at line %d", prev.RuleID, prev.Line, e.owner.RuleID, e.owner.Line)
+		}
+		b.Write(content[last:e.Start])
+		b.WriteString(e.NewText)
+		last = e.End
+	}
+	b.Write(content[last:])
+	return b.Bytes(), nil
+}
+
+// byteOffset returns the offset of the 1-based line and column within
+// content, or -1 if the position is outside of content. Columns count
+// characters, as reported by yaml.v3, not bytes.
+func byteOffset(content []byte, line, column int) int {
+	offset := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(content[offset:], '\n')
+		if i < 0 {
+			return -1
+		}
+		offset += i + 1
+	}
+	if column < 1 {
+		return -1
+	}
+	for c := 1; c < column; c++ {
+		if offset >= len(content) || content[offset] == '\n' {
+			return -1
+		}
+		_, size := utf8.DecodeRune(content[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// unifiedDiff returns a unified diff between the content of the file at path
+// before and after a change, or an empty string if they are equal.
+func unifiedDiff(path string, before, after []byte) string {
+	if bytes.Equal(before, after) {
+		return ""
+	}
+	a, b := splitLinesKeepEnds(before), splitLinesKeepEnds(after)
+
+	// Only the lines between the common prefix and suffix need to be compared.
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+	ops = append(ops, diffLines(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{kind: ' ', line: line})
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, h := range diffHunks(ops) {
+		oldStart, oldLen, newStart, newLen := h.lines(ops)
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(oldStart, oldLen), hunkRange(newStart, newLen))
+		for _, op := range ops[h.start:h.end] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// diffOp is a single line of a diff. kind is ' ' for an unchanged line, '-'
+// for a removed line and '+' for an added line.
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines returns the operations that turn a into b, using the longest
+// common subsequence of lines.
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and
+	// b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: ' ', line: a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// diffHunk is a range [start, end) of diff operations shown together.
+type diffHunk struct {
+	start, end int
+}
+
+// diffHunks groups the changed operations into hunks with diffContext lines
+// of context, merging hunks whose context overlaps.
+func diffHunks(ops []diffOp) []diffHunk {
+	var hunks []diffHunk
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+1+diffContext, len(ops))
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+			hunks[n-1].end = end
+			continue
+		}
+		hunks = append(hunks, diffHunk{start: start, end: end})
+	}
+	return hunks
+}
+
+// lines returns the 1-based start line and number of lines of the hunk in the
+// old and new content.
+func (h diffHunk) lines(ops []diffOp) (oldStart, oldLen, newStart, newLen int) {
+	oldStart, newStart = 1, 1
+	for _, op := range ops[:h.start] {
+		if op.kind != '+' {
+			oldStart++
+		}
+		if op.kind != '-' {
+			newStart++
+		}
+	}
+	for _, op := range ops[h.start:h.end] {
+		if op.kind != '+' {
+			oldLen++
+		}
+		if op.kind != '-' {
+			newLen++
+		}
+	}
+	return oldStart, oldLen, newStart, newLen
+}
+
+// hunkRange formats the range of a hunk header. An empty range refers to the
+// line before it.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start-1)
+	case 1:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d,%d", start, length)
+	}
+}
+
+// splitLinesKeepEnds splits content into lines, keeping the line endings.
+func splitLinesKeepEnds(content []byte) []string {
+	var lines []string
+	for len(content) > 0 {
+		i := bytes.IndexByte(content, '\n')
+		if i < 0 {
+			lines = append(lines, string(content))
+			break
+		}
+		lines = append(lines, string(content[:i+1]))
+		content = content[i+1:]
+	}
+	return lines
+}
+
+// fixFiles applies the fixes in res, grouped by file. With opts.Diff the
+// changes are written to w as unified diffs, otherwise the files are
+// rewritten and the fixed violations are moved to res.Fixed. Files whose
+// fixes overlap are left unchanged and reported in the returned error.
+func fixFiles(res *Result, opts *Options, w io.Writer) error {
+	var fsys fileSystem = osFileSystem{}
+	if opts.FS != nil {
+		fsys = ioFileSystem{fsys: opts.FS}
+	}
+
+	byPath := make(map[string][]*ViolationInstance)
+	var paths []string
+	for _, v := range res.Violations {
+		if v.Fix == nil {
+			continue
+		}
+		if _, ok := byPath[v.Path]; !ok {
+			paths = append(paths, v.Path)
+		}
+		byPath[v.Path] = append(byPath[v.Path], v)
+	}
+
+	var merr error
+	fixed := make(map[*ViolationInstance]bool)
+	for _, path := range paths {
+		violations := byPath[path]
+		content, err := fsys.readFile(path)
+		if err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to fix %s: %w", path, err))
+			continue
+		}
+		after, err := ApplyFixes(content, violations)
+		if err != nil {
+			merr = errors.Join(merr, fmt.Errorf("refusing to fix %s: %w", path, err))
+			continue
+		}
+
+		if opts.Diff {
+			fmt.Fprint(w, unifiedDiff(filepath.ToSlash(path), content, after))
+			continue
+		}
+		if err := writeFilePreservingMode(path, after); err != nil {
+			merr = errors.Join(merr, fmt.Errorf("failed to fix %s: %w", path, err))
+			continue
+		}
+		for _, v := range violations {
+			fixed[v] = true
+		}
+	}
+
+	if len(fixed) > 0 {
+		kept := res.Violations[:0]
+		for _, v := range res.Violations {
+			if fixed[v] {
+				res.Fixed = append(res.Fixed, v)
+				continue
+			}
+			kept = append(kept, v)
+		}
+		res.Violations = kept
+		res.Stats.Violations = len(kept)
+		res.Stats.Fixed = len(res.Fixed)
+		res.Stats.Baselined = 0
+		for _, v := range kept {
+			if v.Baselined {
+				res.Stats.Baselined++
+			}
+		}
+	}
+	return merr
+}
+
+// writeFilePreservingMode replaces the content of the file at path, keeping
+// its permissions.
+func writeFilePreservingMode(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, info.Mode().Perm())
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApplyFixes(t *testing.T) {
+	t.Parallel()
+
+	content := []byte("abcdefghij")
+	fix := func(edits ...TextEdit) *ViolationInstance {
+		return &ViolationInstance{RuleID: "SST003", Line: 1, Fix: &Fix{Edits: edits}}
+	}
+
+	cases := []struct {
+		name       string
+		violations []*ViolationInstance
+		expect     string
+		wantError  string
+	}{
+		{
+			name: "non overlapping",
+			violations: []*ViolationInstance{
+				fix(TextEdit{Start: 7, End: 9, NewText: "HH"}),
+				{RuleID: "SST001"},
+				fix(TextEdit{Start: 0, End: 1, NewText: "A"}, TextEdit{Start: 3, End: 3, NewText: "+"}),
+			},
+			expect: "Abc+defgHHj",
+		},
+		{
+			name: "overlapping",
+			violations: []*ViolationInstance{
+				fix(TextEdit{Start: 2, End: 5, NewText: "x"}),
+				fix(TextEdit{Start: 4, End: 6, NewText: "y"}),
+			},
+			wantError: "overlapping fixes",
+		},
+		{
+			name: "same insertion point",
+			violations: []*ViolationInstance{
+				fix(TextEdit{Start: 2, End: 2, NewText: "x"}),
+				fix(TextEdit{Start: 2, End: 2, NewText: "y"}),
+			},
+			wantError: "overlapping fixes",
+		},
+		{
+			name: "out of range",
+			violations: []*ViolationInstance{
+				fix(TextEdit{Start: 8, End: 20, NewText: "x"}),
+			},
+			wantError: "invalid edit",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ApplyFixes(content, tc.violations)
+			if tc.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+					t.Errorf("expected error containing %q, got %v", tc.wantError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, string(got)); diff != "" {
+				t.Errorf("content (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+const testWorkflow = `name: 'ci'
+on: 'push'
+jobs:
+  plan:
+    steps:
+      # Install terraform.
+      - uses: 'hashicorp/setup-terraform@v2' # pinned
+        with:
+          terraform_version: '1.3.3'
+      - run: 'terraform plan'
+`
+
+// replacementConfig pins the setup-terraform fix, which development builds
+// do not offer by default.
+var replacementConfig = &Config{Rules: map[string]*RuleConfig{
+	ruleIDSetupTerraform: {Options: map[string][]string{optionReplacement: {"abcxyz/secure-setup-terraform@v1"}}},
+}}
+
+func TestRunLinters_Fix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("diff", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeTestFiles(t, dir, map[string]string{"ci.yml": testWorkflow})
+
+		var stdout bytes.Buffer
+		if err := RunLinters(context.Background(), []string{"ci.yml"}, []Linter{&GitHubActionLinter{}}, &Options{
+			FS:     os.DirFS(dir),
+			Stdout: &stdout,
+			Config: replacementConfig,
+			Diff:   true,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `--- a/ci.yml
++++ b/ci.yml
+@@ -4,7 +4,7 @@
+   plan:
+     steps:
+       # Install terraform.
+-      - uses: 'hashicorp/setup-terraform@v2' # pinned
++      - uses: 'abcxyz/secure-setup-terraform@v1' # pinned
+         with:
+           terraform_version: '1.3.3'
+       - run: 'terraform plan'
+`
+		if diff := cmp.Diff(want, stdout.String()); diff != "" {
+			t.Errorf("diff (-want,+got):\n%s", diff)
+		}
+	})
+
+	t.Run("fix", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		writeTestFiles(t, dir, map[string]string{"ci.yml": testWorkflow})
+
+		var got *Result
+		if err := RunLinters(context.Background(), []string{dir}, []Linter{&GitHubActionLinter{}}, &Options{
+			Fix:    true,
+			Config: replacementConfig,
+			Reporter: ReporterFunc(func(res *Result) error {
+				got = res
+				return nil
+			}),
+		}); err != nil {
+			t.Fatalf("expected fixed violations not to fail the run, got %v", err)
+		}
+		if got.Stats.Fixed != 1 || len(got.Violations) != 0 {
+			t.Errorf("expected 1 fixed and no remaining violations, got %+v", got.Stats)
+		}
+
+		b, err := os.ReadFile(filepath.Join(dir, "ci.yml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := strings.Replace(testWorkflow, "hashicorp/setup-terraform@v2", "abcxyz/secure-setup-terraform@v1", 1)
+		if diff := cmp.Diff(want, string(b)); diff != "" {
+			t.Errorf("fixed file (-want,+got):\n%s", diff)
+		}
+	})
+}
@@ -0,0 +1,323 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// fileSystem is the file access used while walking and linting. Paths use the
+// conventions of the underlying file system.
+type fileSystem interface {
+	stat(name string) (fs.FileInfo, error)
+	readDir(name string) ([]fs.DirEntry, error)
+	readFile(name string) ([]byte, error)
+	join(elem ...string) string
+	dir(name string) string
+
+	// evalSymlinks returns the path with symlinks resolved. It is used to
+	// detect symlink loops.
+	evalSymlinks(name string) (string, error)
+}
+
+// osFileSystem accesses the local file system with the os package.
+type osFileSystem struct{}
+
+func (osFileSystem) stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (osFileSystem) readDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (osFileSystem) readFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (osFileSystem) join(elem ...string) string                 { return filepath.Join(elem...) }
+func (osFileSystem) dir(name string) string                     { return filepath.Dir(name) }
+func (osFileSystem) evalSymlinks(name string) (string, error)   { return filepath.EvalSymlinks(name) }
+
+// ioFileSystem accesses an fs.FS. Paths are slash-separated and relative to
+// the root of the FS.
+type ioFileSystem struct {
+	fsys fs.FS
+}
+
+func (f ioFileSystem) stat(name string) (fs.FileInfo, error)      { return fs.Stat(f.fsys, name) }
+func (f ioFileSystem) readDir(name string) ([]fs.DirEntry, error) { return fs.ReadDir(f.fsys, name) }
+func (f ioFileSystem) readFile(name string) ([]byte, error)       { return fs.ReadFile(f.fsys, name) }
+func (f ioFileSystem) join(elem ...string) string                 { return path.Join(elem...) }
+func (f ioFileSystem) dir(name string) string                     { return path.Dir(name) }
+
+// evalSymlinks returns name unchanged, fs.FS has no notion of symlinks.
+func (f ioFileSystem) evalSymlinks(name string) (string, error) { return name, nil }
+
+// ArchiveExtensions are the file extensions recognized by OpenArchive.
+var ArchiveExtensions = []string{".tar.gz", ".tgz", ".zip"}
+
+// IsArchive returns true if path has one of the ArchiveExtensions.
+func IsArchive(path string) bool {
+	for _, ext := range ArchiveExtensions {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// OpenArchive reads a .tar.gz, .tgz or .zip archive, such as a Terraform Cloud
+// configuration version upload, into an in-memory file system. Paths in the
+// returned FS are relative to the root of the archive.
+func OpenArchive(path string) (fs.FS, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive: %w", err)
+	}
+	files, err := readArchive(path, b, defaultArchiveLimits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", path, err)
+	}
+	return NewMemFS(files), nil
+}
+
+// archiveLimits bound the uncompressed size of archives, so a small archive
+// cannot exhaust memory when it is extracted.
+type archiveLimits struct {
+	// file is the maximum size of a single file.
+	file int64
+
+	// total is the maximum size of all files together.
+	total int64
+}
+
+var defaultArchiveLimits = archiveLimits{
+	file:  64 << 20,
+	total: 512 << 20,
+}
+
+// readArchive returns the regular files in an archive, keyed by their path
+// within the archive. It returns an error if a file or the archive as a whole
+// exceeds limits once uncompressed.
+func readArchive(name string, b []byte, limits archiveLimits) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	var total int64
+	read := func(name string, r io.Reader) ([]byte, error) {
+		limit := min(limits.file, limits.total-total)
+		content, err := io.ReadAll(io.LimitReader(r, limit+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		size := int64(len(content))
+		if size > limits.file {
+			return nil, fmt.Errorf("%s exceeds the maximum file size of %d bytes", name, limits.file)
+		}
+		if total+size > limits.total {
+			return nil, fmt.Errorf("archive exceeds the maximum size of %d bytes", limits.total)
+		}
+		total += size
+		return content, nil
+	}
+	add := func(name string, content []byte) {
+		name = path.Clean(strings.TrimPrefix(name, "./"))
+		// Entries that would escape the archive root have nowhere to go.
+		if !fs.ValidPath(name) || name == "." {
+			return
+		}
+		files[name] = content
+	}
+
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if !f.Mode().IsRegular() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to open %s: %w", f.Name, err)
+			}
+			content, err := read(f.Name, rc)
+			rc.Close()
+			if err != nil {
+				return nil, err
+			}
+			add(f.Name, content)
+		}
+		return files, nil
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := read(hdr.Name, tr)
+		if err != nil {
+			return nil, err
+		}
+		add(hdr.Name, content)
+	}
+}
+
+// NewMemFS returns a read-only, in-memory file system containing files, keyed
+// by slash-separated path. Parent directories are created implicitly.
+func NewMemFS(files map[string][]byte) fs.FS {
+	m := &memFS{files: make(map[string][]byte, len(files)), dirs: map[string][]string{".": nil}}
+	for name, content := range files {
+		name = path.Clean(name)
+		m.files[name] = content
+		for child, dir := name, path.Dir(name); ; child, dir = dir, path.Dir(dir) {
+			_, exists := m.dirs[dir]
+			m.dirs[dir] = append(m.dirs[dir], path.Base(child))
+			if exists || dir == "." {
+				break
+			}
+		}
+	}
+	for dir := range m.dirs {
+		sort.Strings(m.dirs[dir])
+	}
+	return m
+}
+
+// memFS is the fs.FS returned by NewMemFS. dirs maps each directory to the
+// sorted names of its children.
+type memFS struct {
+	files map[string][]byte
+	dirs  map[string][]string
+}
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if content, ok := m.files[name]; ok {
+		return &memFile{info: memFileInfo{name: path.Base(name), size: int64(len(content))}, r: bytes.NewReader(content)}, nil
+	}
+	if _, ok := m.dirs[name]; ok {
+		return &memDir{fsys: m, name: name}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (m *memFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	children, ok := m.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for _, child := range children {
+		full := path.Join(name, child)
+		info := memFileInfo{name: child, size: int64(len(m.files[full]))}
+		if _, ok := m.dirs[full]; ok {
+			info.dir = true
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+	return entries, nil
+}
+
+type memFile struct {
+	info memFileInfo
+	r    *bytes.Reader
+}
+
+func (f *memFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memFile) Read(b []byte) (int, error) { return f.r.Read(b) }
+func (f *memFile) Close() error               { return nil }
+
+type memDir struct {
+	fsys *memFS
+	name string
+
+	// entries are the entries not yet returned by ReadDir, loaded on the first
+	// call.
+	entries []fs.DirEntry
+	loaded  bool
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) {
+	return memFileInfo{name: path.Base(d.name), dir: true}, nil
+}
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: errors.New("is a directory")}
+}
+
+func (d *memDir) Close() error { return nil }
+
+// ReadDir implements fs.ReadDirFile.
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.loaded {
+		entries, err := d.fsys.ReadDir(d.name)
+		if err != nil {
+			return nil, err
+		}
+		d.entries, d.loaded = entries, true
+	}
+	if n <= 0 {
+		entries := d.entries
+		d.entries = nil
+		return entries, nil
+	}
+	if len(d.entries) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(d.entries))
+	entries := d.entries[:n]
+	d.entries = d.entries[n:]
+	return entries, nil
+}
+
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun_FS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"main.tf":                  {Data: []byte(testLocalExec)},
+		"modules/net/main.tf":      {Data: []byte(testLocalExec)},
+		".terraform/modules/x.tf":  {Data: []byte(testLocalExec)},
+		"skipped/main.tf":          {Data: []byte(testLocalExec)},
+		"modules/net/variables.tf": {Data: []byte("variable \"a\" {}\n")},
+	}
+
+	res, err := Run(context.Background(), &Options{
+		FS:      fsys,
+		Linters: []Linter{&TerraformLinter{}},
+		Exclude: []string{"skipped"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"main.tf", "modules/net/main.tf", "modules/net/variables.tf"}
+	if diff := cmp.Diff(want, res.Files); diff != "" {
+		t.Errorf("files (-want,+got):\n%s", diff)
+	}
+	var got []string
+	for _, v := range res.Violations {
+		got = append(got, v.Path)
+	}
+	wantViolations := []string{
+		"main.tf", "main.tf", "main.tf",
+		"modules/net/main.tf", "modules/net/main.tf", "modules/net/main.tf",
+	}
+	if diff := cmp.Diff(wantViolations, got); diff != "" {
+		t.Errorf("violations (-want,+got):\n%s", diff)
+	}
+}
+
+func TestNewMemFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := NewMemFS(map[string][]byte{
+		"main.tf":             []byte("a = 1"),
+		"modules/net/main.tf": []byte("b = 2"),
+		"modules/db/main.tf":  []byte("c = 3"),
+	})
+	if err := fstest.TestFS(fsys, "main.tf", "modules/net/main.tf", "modules/db/main.tf"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReadArchive(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"./main.tf":             testLocalExec,
+		"modules/net/main.tf":   "a = 1\n",
+		"../outside/escaped.tf": "b = 2\n",
+	}
+	want := map[string][]byte{
+		"main.tf":             []byte(testLocalExec),
+		"modules/net/main.tf": []byte("a = 1\n"),
+	}
+
+	cases := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "config.tar.gz", content: tarGzArchive(t, files)},
+		{name: "config.zip", content: zipArchive(t, files)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readArchive(tc.name, tc.content, defaultArchiveLimits)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("files (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReadArchive_Limits(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"a.tf": strings.Repeat("a", 60),
+		"b.tf": strings.Repeat("b", 60),
+	}
+
+	cases := []struct {
+		name   string
+		limits archiveLimits
+		expect string
+	}{
+		{
+			name:   "within limits",
+			limits: archiveLimits{file: 60, total: 120},
+		},
+		{
+			name:   "file too large",
+			limits: archiveLimits{file: 50, total: 1000},
+			expect: "exceeds the maximum file size of 50 bytes",
+		},
+		{
+			name:   "archive too large",
+			limits: archiveLimits{file: 100, total: 100},
+			expect: "archive exceeds the maximum size of 100 bytes",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			for name, content := range map[string][]byte{
+				"config.tar.gz": tarGzArchive(t, files),
+				"config.zip":    zipArchive(t, files),
+			} {
+				_, err := readArchive(name, content, tc.limits)
+				if tc.expect == "" {
+					if err != nil {
+						t.Errorf("%s: unexpected error: %v", name, err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tc.expect) {
+					t.Errorf("%s: expected error containing %q, got %v", name, tc.expect, err)
+				}
+			}
+		})
+	}
+}
+
+// tarGzArchive returns a gzipped tarball of files.
+func tarGzArchive(tb testing.TB, files map[string]string) []byte {
+	tb.Helper()
+
+	var tgz bytes.Buffer
+	gw := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gw)
+	if err := tw.WriteHeader(&tar.Header{Name: "modules/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		tb.Fatal(err)
+	}
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			tb.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return tgz.Bytes()
+}
+
+// zipArchive returns a zip archive of files.
+func zipArchive(tb testing.TB, files map[string]string) []byte {
+	tb.Helper()
+
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tb.Fatal(err)
+	}
+	return zipped.Bytes()
+}
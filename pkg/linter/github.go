@@ -0,0 +1,108 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	// githubDataEscaper escapes the message portion of a workflow command.
+	githubDataEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+	)
+
+	// githubPropertyEscaper escapes property values of a workflow command, which
+	// additionally cannot contain the ':' and ',' separators.
+	githubPropertyEscaper = strings.NewReplacer(
+		"%", "%25",
+		"\r", "%0D",
+		"\n", "%0A",
+		":", "%3A",
+		",", "%2C",
+	)
+)
+
+// writeGitHubAnnotations writes the given violations to w as GitHub Actions
+// workflow commands so that each violation is shown as an inline annotation.
+// See https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions.
+func writeGitHubAnnotations(w io.Writer, res *Result) error {
+	for _, v := range res.Violations {
+		props := []string{"file=" + githubPropertyEscaper.Replace(v.Path)}
+		if v.Line > 0 {
+			props = append(props, "line="+strconv.Itoa(v.Line))
+		}
+		if v.Column > 0 {
+			props = append(props, "col="+strconv.Itoa(v.Column))
+		}
+		if v.EndLine > 0 {
+			props = append(props, "endLine="+strconv.Itoa(v.EndLine))
+		}
+		// EndColumn is one past the last character, while GitHub expects the
+		// last character itself.
+		if end := v.EndColumn - 1; end > 0 && (v.EndLine != v.Line || end >= v.Column) {
+			props = append(props, "endColumn="+strconv.Itoa(end))
+		}
+		title := v.ViolationType
+		if v.RuleID != "" {
+			title = v.RuleID + " " + title
+		}
+		props = append(props, "title="+githubPropertyEscaper.Replace(title))
+
+		msg := violationMessage(v)
+		if v.Object != "" {
+			msg += " (in " + v.Object + ")"
+		}
+		if v.Remediation != "" {
+			msg += "\n" + v.Remediation
+		}
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(v.Severity), strings.Join(props, ","), githubDataEscaper.Replace(msg)); err != nil {
+			return fmt.Errorf("failed to write annotation: %w", err)
+		}
+	}
+
+	for _, d := range res.Diagnostics {
+		props := []string{"file=" + githubPropertyEscaper.Replace(d.Path)}
+		if d.Line > 0 {
+			props = append(props, "line="+strconv.Itoa(d.Line))
+		}
+		if d.Column > 0 {
+			props = append(props, "col="+strconv.Itoa(d.Column))
+		}
+		props = append(props, "title="+githubPropertyEscaper.Replace("failed to lint file"))
+		if _, err := fmt.Fprintf(w, "::error %s::%s\n", strings.Join(props, ","), githubDataEscaper.Replace(d.Message)); err != nil {
+			return fmt.Errorf("failed to write annotation: %w", err)
+		}
+	}
+	return nil
+}
+
+// githubCommand returns the workflow command used to annotate a violation with
+// the given severity.
+func githubCommand(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "notice"
+	default:
+		return "error"
+	}
+}
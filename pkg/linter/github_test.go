@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteGitHubAnnotations(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		violations  []*ViolationInstance
+		diagnostics []*Diagnostic
+		expect      string
+	}{
+		{
+			name:       "no violations",
+			violations: nil,
+			expect:     "",
+		},
+		{
+			name: "multiple violations",
+			violations: []*ViolationInstance{
+				newViolation("local-exec", "main.tf", span{23, 15, 23, 27}, "null_resource.echo"),
+				{ViolationType: "setup-terraform", Severity: SeverityWarning, Path: ".github/workflows/ci.yml", Line: 31},
+			},
+			expect: "::error file=main.tf,line=23,col=15,endLine=23,endColumn=26,title=SST001 local-exec::" +
+				"Provisioner \"local-exec\" runs arbitrary commands on the machine running Terraform. (in null_resource.echo)" +
+				"%0ARemove the provisioner and move the command into a separate, reviewed build step.\n" +
+				"::warning file=.github/workflows/ci.yml,line=31,title=setup-terraform::\"setup-terraform\" detected\n",
+		},
+		{
+			name: "escapes properties",
+			violations: []*ViolationInstance{
+				{ViolationType: "local-exec", Path: "dir,with:odd%chars/main.tf", Line: 1},
+			},
+			expect: "::error file=dir%2Cwith%3Aodd%25chars/main.tf,line=1,title=local-exec::\"local-exec\" detected\n",
+		},
+		{
+			name: "diagnostics",
+			diagnostics: []*Diagnostic{
+				{Path: "broken.tf", Line: 3, Column: 7, Message: "Argument or block definition required"},
+				{Path: "unreadable", Message: "error reading directory: permission denied"},
+			},
+			expect: "::error file=broken.tf,line=3,col=7,title=failed to lint file::Argument or block definition required\n" +
+				"::error file=unreadable,title=failed to lint file::error reading directory: permission denied\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := writeGitHubAnnotations(&b, &Result{Violations: tc.violations, Diagnostics: tc.diagnostics}); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, b.String()); diff != "" {
+				t.Errorf("output (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	cases := []struct {
+		name          string
+		input         string
+		githubActions string
+		expect        Format
+		wantError     bool
+	}{
+		{
+			name:   "text",
+			input:  "text",
+			expect: FormatText,
+		},
+		{
+			name:   "sarif",
+			input:  "sarif",
+			expect: FormatSARIF,
+		},
+		{
+			name:   "github",
+			input:  "github",
+			expect: FormatGitHub,
+		},
+		{
+			name:   "junit",
+			input:  "junit",
+			expect: FormatJUnit,
+		},
+		{
+			name:   "codequality",
+			input:  "codequality",
+			expect: FormatCodeQuality,
+		},
+		{
+			name:      "unknown",
+			input:     "xml",
+			wantError: true,
+		},
+		{
+			name:   "default outside actions",
+			input:  "",
+			expect: FormatText,
+		},
+		{
+			name:          "default inside actions",
+			input:         "",
+			githubActions: "true",
+			expect:        FormatGitHub,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("GITHUB_ACTIONS", tc.githubActions)
+
+			got, err := ParseFormat(tc.input)
+			if tc.wantError != (err != nil) {
+				t.Errorf("expected error want: %#v, got: %#v - error: %v", tc.wantError, err != nil, err)
+			}
+			if got != tc.expect {
+				t.Errorf("expected format %q, got %q", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// gitignoreFileName is the name of git's per-directory ignore file.
+const gitignoreFileName = ".gitignore"
+
+// gitignorePattern is a single line of a .gitignore file.
+type gitignorePattern struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// gitignore is the set of patterns read from a single .gitignore file. It
+// supports the commonly used subset of the gitignore syntax: comments,
+// negation with '!', directory-only patterns ending in '/', patterns anchored
+// to the .gitignore directory, and '*', '?', '[...]' and '**' wildcards.
+type gitignore struct {
+	// dir is the directory containing the .gitignore file.
+	dir      string
+	patterns []*gitignorePattern
+}
+
+// parseGitignore parses the contents of a .gitignore file in dir.
+func parseGitignore(dir string, content []byte) *gitignore {
+	g := &gitignore{dir: dir}
+	for _, line := range splitLines(content) {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := &gitignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		// A slash anywhere but the end anchors the pattern to dir.
+		p.anchored = strings.Contains(line, "/")
+		p.pattern = strings.TrimPrefix(line, "/")
+		if p.pattern == "" || validateGlob(p.pattern) != nil {
+			continue
+		}
+		g.patterns = append(g.patterns, p)
+	}
+	return g
+}
+
+// match reports whether path is ignored (true, true), explicitly re-included
+// (false, true), or not mentioned (false, false) by this file.
+func (g *gitignore) match(path string, isDir bool) (ignored, matched bool) {
+	rel, err := filepath.Rel(g.dir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return false, false
+	}
+	rel = filepath.ToSlash(rel)
+
+	// The last matching pattern wins.
+	for i := len(g.patterns) - 1; i >= 0; i-- {
+		p := g.patterns[i]
+		if p.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if p.anchored {
+			ok = matchAnchoredGlob(p.pattern, rel)
+		} else {
+			ok = matchGlob(p.pattern, rel)
+		}
+		if ok {
+			return !p.negate, true
+		}
+	}
+	return false, false
+}
+
+// gitignoreStack is the list of .gitignore files that apply to a directory,
+// outermost first.
+type gitignoreStack []*gitignore
+
+// ignored reports whether path is ignored by any of the files, with files
+// closer to path taking precedence.
+func (s gitignoreStack) ignored(path string, isDir bool) bool {
+	for i := len(s) - 1; i >= 0; i-- {
+		if ignored, matched := s[i].match(path, isDir); matched {
+			return ignored
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		name    string
+		expect  bool
+	}{
+		{pattern: "*.tf", name: "main.tf", expect: true},
+		{pattern: "*.tf", name: "modules/net/main.tf", expect: true},
+		{pattern: "*.tf", name: "main.tf.json", expect: false},
+		{pattern: "examples/**", name: "examples/a/main.tf", expect: true},
+		{pattern: "examples/**", name: "modules/examples/main.tf", expect: false},
+		{pattern: "**/examples/**", name: "modules/examples/main.tf", expect: true},
+		{pattern: "modules/*/main.tf", name: "modules/net/main.tf", expect: true},
+		{pattern: "modules/*/main.tf", name: "modules/net/sub/main.tf", expect: false},
+		{pattern: "modules/**/main.tf", name: "modules/main.tf", expect: true},
+		{pattern: "./modules/**", name: "modules/net/main.tf", expect: true},
+		{pattern: ".terraform", name: "envs/prod/.terraform", expect: true},
+		{pattern: "vendor/", name: "vendor", expect: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.pattern+" "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := matchGlob(tc.pattern, tc.name); got != tc.expect {
+				t.Errorf("matchGlob(%q, %q) = %t, want %t", tc.pattern, tc.name, got, tc.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+// JSONSchemaVersion is the version of the JSON and NDJSON report schema. It is
+// incremented whenever a field is removed or changes meaning; new fields may be
+// added without changing the version.
+const JSONSchemaVersion = 1
+
+// Record kinds used in the NDJSON stream.
+const (
+	ndjsonKindRun         = "run"
+	ndjsonKindFile        = "file"
+	ndjsonKindViolation   = "violation"
+	ndjsonKindSuppression = "suppression"
+	ndjsonKindParseError  = "parse_error"
+	ndjsonKindSummary     = "summary"
+)
+
+type jsonReport struct {
+	SchemaVersion        int                `json:"schema_version"`
+	Tool                 *jsonTool          `json:"tool"`
+	Files                []string           `json:"files"`
+	Violations           []*jsonViolation   `json:"violations"`
+	SuppressedViolations []*jsonViolation   `json:"suppressed_violations"`
+	ParseErrors          []*jsonParseError  `json:"parse_errors"`
+	Suppressions         []*jsonSuppression `json:"suppressions"`
+	Summary              *jsonReportSummary `json:"summary"`
+}
+
+type jsonTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+type jsonViolation struct {
+	Type        string   `json:"type"`
+	RuleID      string   `json:"rule_id"`
+	Severity    Severity `json:"severity"`
+	Message     string   `json:"message"`
+	Remediation string   `json:"remediation,omitempty"`
+	Path        string   `json:"path"`
+	Line        int      `json:"line"`
+	Column      int      `json:"column,omitempty"`
+	EndLine     int      `json:"end_line,omitempty"`
+	EndColumn   int      `json:"end_column,omitempty"`
+	Object      string   `json:"object,omitempty"`
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Baselined   bool     `json:"baselined,omitempty"`
+	Suppressed  bool     `json:"suppressed,omitempty"`
+	Fix         *Fix     `json:"fix,omitempty"`
+}
+
+type jsonParseError struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type jsonSuppression struct {
+	Path    string            `json:"path"`
+	Line    int               `json:"line"`
+	Rules   []string          `json:"rules"`
+	Reason  string            `json:"reason,omitempty"`
+	Expires string            `json:"expires,omitempty"`
+	Status  SuppressionStatus `json:"status"`
+	Problem string            `json:"problem,omitempty"`
+}
+
+type jsonReportSummary struct {
+	FilesScanned int `json:"files_scanned"`
+	Violations   int `json:"violations"`
+	Suppressed   int `json:"suppressed"`
+	ParseErrors  int `json:"parse_errors"`
+}
+
+// ndjsonRecord is a single line of the NDJSON stream. Kind determines which of
+// the other fields is set.
+type ndjsonRecord struct {
+	Kind          string             `json:"kind"`
+	SchemaVersion int                `json:"schema_version,omitempty"`
+	Tool          *jsonTool          `json:"tool,omitempty"`
+	Path          string             `json:"path,omitempty"`
+	Violation     *jsonViolation     `json:"violation,omitempty"`
+	Suppression   *jsonSuppression   `json:"suppression,omitempty"`
+	ParseError    *jsonParseError    `json:"parse_error,omitempty"`
+	Summary       *jsonReportSummary `json:"summary,omitempty"`
+}
+
+// writeJSON writes the result to w as a single JSON document. Violations
+// removed by a suppression comment are listed separately from the others.
+func writeJSON(w io.Writer, res *Result) error {
+	report := &jsonReport{
+		SchemaVersion:        JSONSchemaVersion,
+		Tool:                 currentJSONTool(),
+		Files:                make([]string, 0, len(res.Files)),
+		Violations:           make([]*jsonViolation, 0, len(res.Violations)),
+		SuppressedViolations: make([]*jsonViolation, 0, len(res.Suppressed)),
+		ParseErrors:          make([]*jsonParseError, 0, len(res.Diagnostics)),
+		Suppressions:         make([]*jsonSuppression, 0, len(res.Suppressions)),
+		Summary:              jsonSummary(res),
+	}
+	report.Files = append(report.Files, res.Files...)
+	for _, v := range res.Violations {
+		report.Violations = append(report.Violations, toJSONViolation(v))
+	}
+	for _, v := range res.Suppressed {
+		jv := toJSONViolation(v)
+		jv.Suppressed = true
+		report.SuppressedViolations = append(report.SuppressedViolations, jv)
+	}
+	for _, d := range res.Diagnostics {
+		report.ParseErrors = append(report.ParseErrors, toJSONParseError(d))
+	}
+	for _, sup := range res.Suppressions {
+		report.Suppressions = append(report.Suppressions, toJSONSuppression(sup))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode json report: %w", err)
+	}
+	return nil
+}
+
+// writeNDJSON writes the result to w as newline-delimited JSON records, one
+// per line. The first is a "run" record and the last is a "summary" record.
+func writeNDJSON(w io.Writer, res *Result) error {
+	n := newNDJSONWriter(w)
+	if err := n.begin(); err != nil {
+		return err
+	}
+	if err := n.records(res); err != nil {
+		return err
+	}
+	return n.end(res)
+}
+
+// ndjsonWriter writes the NDJSON records of a run in stages, so that the
+// records of each file can be written as soon as it has been linted.
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+// begin writes the "run" record.
+func (n *ndjsonWriter) begin() error {
+	return n.write(&ndjsonRecord{
+		Kind:          ndjsonKindRun,
+		SchemaVersion: JSONSchemaVersion,
+		Tool:          currentJSONTool(),
+	})
+}
+
+// records writes the files, violations, parse errors and suppressions of res.
+// Violations removed by a suppression comment are marked as suppressed.
+func (n *ndjsonWriter) records(res *Result) error {
+	for _, f := range res.Files {
+		if err := n.write(&ndjsonRecord{Kind: ndjsonKindFile, Path: f}); err != nil {
+			return err
+		}
+	}
+	for _, v := range res.Violations {
+		if err := n.write(&ndjsonRecord{Kind: ndjsonKindViolation, Violation: toJSONViolation(v)}); err != nil {
+			return err
+		}
+	}
+	for _, v := range res.Suppressed {
+		jv := toJSONViolation(v)
+		jv.Suppressed = true
+		if err := n.write(&ndjsonRecord{Kind: ndjsonKindViolation, Violation: jv}); err != nil {
+			return err
+		}
+	}
+	for _, d := range res.Diagnostics {
+		if err := n.write(&ndjsonRecord{Kind: ndjsonKindParseError, ParseError: toJSONParseError(d)}); err != nil {
+			return err
+		}
+	}
+	for _, sup := range res.Suppressions {
+		if err := n.write(&ndjsonRecord{Kind: ndjsonKindSuppression, Suppression: toJSONSuppression(sup)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// end writes the "summary" record of the whole run.
+func (n *ndjsonWriter) end(res *Result) error {
+	return n.write(&ndjsonRecord{Kind: ndjsonKindSummary, Summary: jsonSummary(res)})
+}
+
+func (n *ndjsonWriter) write(r *ndjsonRecord) error {
+	if err := n.enc.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode %s record: %w", r.Kind, err)
+	}
+	return nil
+}
+
+func currentJSONTool() *jsonTool {
+	return &jsonTool{
+		Name:    version.Name,
+		Version: version.Version,
+		Commit:  version.Commit,
+	}
+}
+
+func toJSONViolation(v *ViolationInstance) *jsonViolation {
+	return &jsonViolation{
+		Type:        v.ViolationType,
+		RuleID:      v.RuleID,
+		Severity:    v.Severity,
+		Message:     violationMessage(v),
+		Remediation: v.Remediation,
+		Path:        v.Path,
+		Line:        v.Line,
+		Column:      v.Column,
+		EndLine:     v.EndLine,
+		EndColumn:   v.EndColumn,
+		Object:      v.Object,
+		Fingerprint: v.Fingerprint,
+		Baselined:   v.Baselined,
+		Fix:         v.Fix,
+	}
+}
+
+func toJSONParseError(d *Diagnostic) *jsonParseError {
+	return &jsonParseError{
+		Path:    d.Path,
+		Line:    d.Line,
+		Column:  d.Column,
+		Message: d.Message,
+	}
+}
+
+func toJSONSuppression(s *Suppression) *jsonSuppression {
+	js := &jsonSuppression{
+		Path:    s.Path,
+		Line:    s.Line,
+		Rules:   s.Rules,
+		Reason:  s.Reason,
+		Status:  s.Status,
+		Problem: s.Problem,
+	}
+	if !s.Expires.IsZero() {
+		js.Expires = s.Expires.Format(suppressionDateLayout)
+	}
+	return js
+}
+
+func jsonSummary(res *Result) *jsonReportSummary {
+	return &jsonReportSummary{
+		FilesScanned: len(res.Files),
+		Violations:   len(res.Violations),
+		Suppressed:   len(res.Suppressed),
+		ParseErrors:  len(res.Diagnostics),
+	}
+}
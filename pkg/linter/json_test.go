@@ -0,0 +1,218 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testLintResult() *Result {
+	return &Result{
+		Files: []string{"main.tf", "other.tf"},
+		Violations: []*ViolationInstance{
+			newViolation("local-exec", "main.tf", span{3, 15, 3, 27}, "null_resource.echo"),
+		},
+		Suppressed: []*ViolationInstance{
+			newViolation("remote-exec", "other.tf", span{4, 15, 4, 28}, "null_resource.echo"),
+		},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	if err := writeJSON(&b, testLintResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	got.Tool = nil
+
+	want := jsonReport{
+		SchemaVersion: JSONSchemaVersion,
+		Files:         []string{"main.tf", "other.tf"},
+		Violations: []*jsonViolation{
+			{
+				Type:        "local-exec",
+				RuleID:      "SST001",
+				Severity:    SeverityError,
+				Message:     `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+				Remediation: "Remove the provisioner and move the command into a separate, reviewed build step.",
+				Path:        "main.tf",
+				Line:        3,
+				Column:      15,
+				EndLine:     3,
+				EndColumn:   27,
+				Object:      "null_resource.echo",
+			},
+		},
+		SuppressedViolations: []*jsonViolation{
+			{
+				Type:        "remote-exec",
+				RuleID:      "SST002",
+				Severity:    SeverityError,
+				Message:     `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`,
+				Remediation: "Remove the provisioner and configure the host with startup scripts or images instead.",
+				Path:        "other.tf",
+				Line:        4,
+				Column:      15,
+				EndLine:     4,
+				EndColumn:   28,
+				Object:      "null_resource.echo",
+				Suppressed:  true,
+			},
+		},
+		ParseErrors:  []*jsonParseError{},
+		Suppressions: []*jsonSuppression{},
+		Summary: &jsonReportSummary{
+			FilesScanned: 2,
+			Violations:   1,
+			Suppressed:   1,
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("report (-want,+got):\n%s", diff)
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var b bytes.Buffer
+	if err := writeNDJSON(&b, testLintResult()); err != nil {
+		t.Fatal(err)
+	}
+
+	var kinds []string
+	for _, r := range decodeNDJSON(t, &b) {
+		kind := r.Kind
+		if r.Violation != nil && r.Violation.Suppressed {
+			kind += " (suppressed)"
+		}
+		kinds = append(kinds, kind)
+	}
+
+	want := []string{"run", "file", "file", "violation", "violation (suppressed)", "summary"}
+	if diff := cmp.Diff(want, kinds); diff != "" {
+		t.Errorf("record kinds (-want,+got):\n%s", diff)
+	}
+}
+
+func TestRunLinters_StreamsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	// The second file is only linted once the records of the first one have
+	// been written.
+	stdout := &watchWriter{want: `{"kind":"file","path":"a.tf"}`, seen: make(chan struct{})}
+	l := &waitingLinter{path: "b.tf", wait: stdout.seen}
+
+	err := RunLinters(context.Background(), nil, []Linter{l}, &Options{
+		FS: fstest.MapFS{
+			"a.tf": {Data: []byte("a = 1\n")},
+			"b.tf": {Data: []byte("b = 2\n")},
+		},
+		Format:  FormatNDJSON,
+		Stdout:  stdout,
+		Workers: 2,
+	})
+	if err == nil {
+		t.Error("expected violations")
+	}
+	if l.timedOut {
+		t.Error("expected the records of a.tf before b.tf was linted")
+	}
+
+	var got []string
+	for _, r := range decodeNDJSON(t, &stdout.buf) {
+		switch {
+		case r.Path != "":
+			got = append(got, r.Kind+" "+r.Path)
+		case r.Violation != nil:
+			got = append(got, r.Kind+" "+r.Violation.Path)
+		default:
+			got = append(got, r.Kind)
+		}
+	}
+	want := []string{"run", "file a.tf", "violation a.tf", "file b.tf", "violation b.tf", "summary"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("records (-want,+got):\n%s", diff)
+	}
+}
+
+// watchWriter buffers everything written to it and closes seen once want has
+// been written.
+type watchWriter struct {
+	buf  bytes.Buffer
+	want string
+	seen chan struct{}
+	once sync.Once
+}
+
+func (w *watchWriter) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.want) {
+		w.once.Do(func() { close(w.seen) })
+	}
+	return w.buf.Write(p)
+}
+
+// waitingLinter reports a violation in every .tf file. Linting the file at
+// path waits for wait to be closed.
+type waitingLinter struct {
+	path     string
+	wait     <-chan struct{}
+	timedOut bool
+}
+
+func (l *waitingLinter) Selectors() []string { return []string{".tf"} }
+
+func (l *waitingLinter) FindViolations(content []byte, path string) ([]*ViolationInstance, error) {
+	if path == l.path {
+		select {
+		case <-l.wait:
+		case <-time.After(10 * time.Second):
+			l.timedOut = true
+		}
+	}
+	return []*ViolationInstance{{ViolationType: "test", Path: path, Line: 1}}, nil
+}
+
+func decodeNDJSON(t *testing.T, b *bytes.Buffer) []*ndjsonRecord {
+	t.Helper()
+
+	var records []*ndjsonRecord
+	scanner := bufio.NewScanner(b)
+	for scanner.Scan() {
+		var r ndjsonRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("failed to decode record %q: %v", scanner.Text(), err)
+		}
+		records = append(records, &r)
+	}
+	return records
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitLintCase is the name of the test case used for files that could not be
+// linted, since no rule ran against them.
+const junitLintCase = "lint"
+
+// writeJUnit writes the result to w as a JUnit XML report. Every scanned file is
+// a test suite with one test case for each enabled rule that applies to it, so CI
+// dashboards show passing rules as well as failures. Violations of a rule are
+// combined into a single failure. Baselined violations do not fail the test
+// case and are listed in its output instead. Files that could not be linted
+// have a single test case with an error.
+func writeJUnit(w io.Writer, res *Result) error {
+	type key struct{ path, rule string }
+	violations := make(map[key][]*ViolationInstance)
+	var keys []key
+	for _, v := range res.Violations {
+		k := key{v.Path, v.RuleID}
+		if k.rule == "" {
+			k.rule = v.ViolationType
+		}
+		if r := LookupRule(k.rule); r != nil {
+			k.rule = r.Info().ID
+		}
+		if _, ok := violations[k]; !ok {
+			keys = append(keys, k)
+		}
+		violations[k] = append(violations[k], v)
+	}
+	diagnostics := make(map[string][]*Diagnostic)
+	for _, d := range res.Diagnostics {
+		diagnostics[d.Path] = append(diagnostics[d.Path], d)
+	}
+
+	rules := res.rules
+	if rules == nil {
+		rules = Rules()
+	}
+
+	report := &junitTestSuites{Name: version.Name}
+	seen := make(map[string]bool, len(res.Files))
+	addSuite := func(path string) {
+		seen[path] = true
+		suite := &junitTestSuite{Name: path}
+		if diags := diagnostics[path]; len(diags) > 0 {
+			msgs := make([]string, 0, len(diags))
+			for _, d := range diags {
+				msgs = append(msgs, fmt.Sprintf("%s: %s", diagnosticLocation(d), d.Message))
+			}
+			suite.Cases = append(suite.Cases, &junitTestCase{
+				Name:      junitLintCase,
+				ClassName: path,
+				Error: &junitFailure{
+					Message: "failed to lint file",
+					Text:    strings.Join(msgs, "\n"),
+				},
+			})
+			suite.Errors++
+		} else {
+			reported := make(map[key]bool)
+			for _, r := range rules {
+				if !ruleApplies(r, path) {
+					continue
+				}
+				info := r.Info()
+				k := key{path, info.ID}
+				reported[k] = true
+				suite.Cases = append(suite.Cases, junitCase(path, info.ID+" "+info.Name, violations[k]))
+			}
+			// Violations of rules that are not registered, or reported by a
+			// linter for files the rule does not normally apply to.
+			for _, k := range keys {
+				if k.path != path || reported[k] {
+					continue
+				}
+				name := k.rule
+				if r := LookupRule(k.rule); r != nil {
+					name += " " + r.Info().Name
+				}
+				suite.Cases = append(suite.Cases, junitCase(path, name, violations[k]))
+			}
+			for _, c := range suite.Cases {
+				if c.Failure != nil {
+					suite.Failures++
+				}
+			}
+		}
+		suite.Tests = len(suite.Cases)
+
+		report.Suites = append(report.Suites, suite)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+	}
+	for _, path := range res.Files {
+		addSuite(path)
+	}
+	// Directories that could not be read are not scanned files but still need
+	// to be reported, as do violations in results built without Files.
+	for _, d := range res.Diagnostics {
+		if !seen[d.Path] {
+			addSuite(d.Path)
+		}
+	}
+	for _, k := range keys {
+		if !seen[k.path] {
+			addSuite(k.path)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write junit report: %w", err)
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return fmt.Errorf("failed to encode junit report: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write junit report: %w", err)
+	}
+	return nil
+}
+
+// junitCase builds the test case for a rule in a file from the violations of
+// that rule.
+func junitCase(path, name string, violations []*ViolationInstance) *junitTestCase {
+	c := &junitTestCase{Name: name, ClassName: path}
+
+	var failures, baselined []string
+	var first *ViolationInstance
+	for _, v := range violations {
+		text := fmt.Sprintf("%s: %s", textLocation(v), violationMessage(v))
+		if v.Object != "" {
+			text += " (in " + v.Object + ")"
+		}
+		if v.Baselined {
+			baselined = append(baselined, text+" (baselined)")
+			continue
+		}
+		if first == nil {
+			first = v
+		}
+		failures = append(failures, text)
+	}
+	if first != nil {
+		if first.Remediation != "" {
+			failures = append(failures, first.Remediation)
+		}
+		c.Failure = &junitFailure{
+			Message: violationMessage(first),
+			Type:    string(first.Severity),
+			Text:    strings.Join(failures, "\n"),
+		}
+	}
+	c.SystemOut = strings.Join(baselined, "\n")
+	return c
+}
+
+// ruleApplies returns true if rule runs against the file at path.
+func ruleApplies(rule Rule, path string) bool {
+	var selectors []string
+	switch rule.(type) {
+	case TerraformRule:
+		selectors = terraformSelectors
+	case WorkflowRule:
+		selectors = actionSelectors
+	}
+	for _, sel := range selectors {
+		if strings.HasSuffix(path, sel) {
+			return true
+		}
+	}
+	return false
+}
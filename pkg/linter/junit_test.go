@@ -0,0 +1,156 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	t.Parallel()
+
+	baselined := newViolation("remote-exec", "main.tf", span{9, 15, 9, 28}, "null_resource.old")
+	baselined.Baselined = true
+	res := &Result{
+		Files: []string{"main.tf", "broken.tf", ".github/workflows/ci.yml"},
+		Violations: []*ViolationInstance{
+			newViolation("local-exec", "main.tf", span{3, 15, 3, 27}, "null_resource.echo"),
+			newViolation("local-exec", "main.tf", span{5, 15, 5, 27}, "null_resource.echo"),
+			baselined,
+		},
+		Diagnostics: []*Diagnostic{
+			{Path: "broken.tf", Line: 2, Column: 1, Message: "Argument or block definition required"},
+		},
+	}
+
+	var b bytes.Buffer
+	if err := writeJUnit(&b, res); err != nil {
+		t.Fatal(err)
+	}
+
+	var got junitTestSuites
+	if err := xml.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode report: %v\n%s", err, b.String())
+	}
+
+	if got, want := len(got.Suites), 3; got != want {
+		t.Fatalf("expected %d test suites, got %d", want, got)
+	}
+	if got.Failures != 1 || got.Errors != 1 {
+		t.Errorf("expected 1 failure and 1 error, got %d failures and %d errors", got.Failures, got.Errors)
+	}
+
+	// Each file has a case for every rule that applies to it, so only compare
+	// the cases with findings and check that passing rules are present.
+	cases := make(map[string]*junitTestCase)
+	for _, suite := range got.Suites {
+		if suite.Tests != len(suite.Cases) {
+			t.Errorf("suite %s: tests=%d, but has %d cases", suite.Name, suite.Tests, len(suite.Cases))
+		}
+		for _, c := range suite.Cases {
+			cases[c.ClassName+" "+c.Name] = c
+		}
+	}
+
+	want := map[string]*junitTestCase{
+		"main.tf SST001 local-exec": {
+			Name:      "SST001 local-exec",
+			ClassName: "main.tf",
+			Failure: &junitFailure{
+				Message: `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+				Type:    "error",
+				Text: `main.tf:3:15: Provisioner "local-exec" runs arbitrary commands on the machine running Terraform. (in null_resource.echo)` + "\n" +
+					`main.tf:5:15: Provisioner "local-exec" runs arbitrary commands on the machine running Terraform. (in null_resource.echo)` + "\n" +
+					"Remove the provisioner and move the command into a separate, reviewed build step.",
+			},
+		},
+		"main.tf SST002 remote-exec": {
+			Name:      "SST002 remote-exec",
+			ClassName: "main.tf",
+			SystemOut: `main.tf:9:15: Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform. (in null_resource.old) (baselined)`,
+		},
+		"broken.tf lint": {
+			Name:      "lint",
+			ClassName: "broken.tf",
+			Error: &junitFailure{
+				Message: "failed to lint file",
+				Text:    "broken.tf:2:1: Argument or block definition required",
+			},
+		},
+		".github/workflows/ci.yml SST003 setup-terraform": {
+			Name:      "SST003 setup-terraform",
+			ClassName: ".github/workflows/ci.yml",
+		},
+	}
+	for name, wantCase := range want {
+		if diff := cmp.Diff(wantCase, cases[name]); diff != "" {
+			t.Errorf("test case %q (-want,+got):\n%s", name, diff)
+		}
+	}
+	if _, ok := cases["main.tf SST003 setup-terraform"]; ok {
+		t.Errorf("workflow rule reported for a Terraform file")
+	}
+}
+
+func TestWriteJUnit_DisabledRules(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]byte(`
+version: 1
+rules:
+  remote-exec:
+    enabled: false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Run(context.Background(), &Options{
+		FS:      fstest.MapFS{"main.tf": {Data: []byte(testLocalExec)}},
+		Linters: []Linter{&TerraformLinter{}},
+		Config:  cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeJUnit(&b, res); err != nil {
+		t.Fatal(err)
+	}
+	var got junitTestSuites
+	if err := xml.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode report: %v\n%s", err, b.String())
+	}
+
+	var names []string
+	for _, suite := range got.Suites {
+		for _, c := range suite.Cases {
+			names = append(names, c.Name)
+		}
+	}
+	if !slices.Contains(names, "SST001 local-exec") {
+		t.Errorf("expected a case for the enabled local-exec rule, got %v", names)
+	}
+	if slices.Contains(names, "SST002 remote-exec") {
+		t.Errorf("expected no case for the disabled remote-exec rule, got %v", names)
+	}
+}
@@ -16,18 +16,59 @@ package linter
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
-	"strings"
+	"time"
 )
 
 // ViolationInstance is an object that contains a reference to a location
 // in a file where a lint violation was detected.
 type ViolationInstance struct {
+	// ViolationType is the short name of the violated rule, for example
+	// "local-exec".
 	ViolationType string
-	Path          string
-	Line          int
+
+	// RuleID is the stable identifier of the violated rule, for example
+	// "SST001".
+	RuleID string
+
+	// Severity is how serious the violation is.
+	Severity Severity
+
+	// Message is a human readable description of the violation.
+	Message string
+
+	// Remediation is a hint describing how to fix the violation.
+	Remediation string
+
+	// Path is the path of the file containing the violation.
+	Path string
+
+	// Line and Column are the 1-based start of the violation. EndLine and
+	// EndColumn are the 1-based end of the violation, exclusive.
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+
+	// Object is the name of the object enclosing the violation, for example a
+	// resource address ("null_resource.echo") or a workflow step
+	// ("jobs.build.steps[0]"). It is empty when unknown.
+	Object string
+
+	// Fingerprint identifies the violation independently of its line number. It
+	// is derived from the rule, the path and the content of the enclosing block.
+	Fingerprint string
+
+	// Baselined is true if the violation is present in the baseline. Baselined
+	// violations are reported as warnings and do not fail the run.
+	Baselined bool
+
+	// Fix is a mechanical fix for the violation, or nil if there is none.
+	Fix *Fix
 }
 
 // Linter defines an interface selecting a set of files to apply lint rules
@@ -41,70 +82,557 @@ type Linter interface {
 	FindViolations(content []byte, path string) ([]*ViolationInstance, error)
 }
 
+// Format is the format used to report violations.
+type Format string
+
+const (
+	// FormatText prints one line per violation.
+	FormatText Format = "text"
+
+	// FormatSARIF prints a SARIF 2.1.0 log suitable for GitHub code scanning.
+	FormatSARIF Format = "sarif"
+
+	// FormatGitHub prints GitHub Actions workflow commands so that violations
+	// are shown as annotations on the pull request.
+	FormatGitHub Format = "github"
+
+	// FormatJSON prints a single, versioned JSON report. See JSONSchemaVersion.
+	FormatJSON Format = "json"
+
+	// FormatNDJSON prints the JSON report as newline-delimited records. The
+	// records of each file are written as soon as it has been linted.
+	FormatNDJSON Format = "ndjson"
+
+	// FormatJUnit prints a JUnit XML report with one test case per file and
+	// rule, for CI systems such as Jenkins.
+	FormatJUnit Format = "junit"
+
+	// FormatCodeQuality prints a GitLab Code Quality report.
+	FormatCodeQuality Format = "codequality"
+)
+
+// Formats is the list of supported output formats.
+var Formats = []Format{FormatText, FormatSARIF, FormatGitHub, FormatJSON, FormatNDJSON, FormatJUnit, FormatCodeQuality}
+
+// DefaultFormat returns the format to use when none was requested. When
+// running inside GitHub Actions this is FormatGitHub, otherwise FormatText.
+func DefaultFormat() Format {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		return FormatGitHub
+	}
+	return FormatText
+}
+
+// ParseFormat converts a string into a Format, returning an error if the format
+// is not supported. An empty string selects the DefaultFormat.
+func ParseFormat(s string) (Format, error) {
+	if s == "" {
+		return DefaultFormat(), nil
+	}
+	if f := Format(s); validFormat(f) {
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, must be one of %q", s, Formats)
+}
+
+func validFormat(f Format) bool {
+	for _, format := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// Options are the options for a linter run.
+type Options struct {
+	// Paths are the files and directories to lint. Used by Run; the RunLinter
+	// functions take the paths as an argument instead.
+	Paths []string
+
+	// Linters are the linters to run. Each file is handled by the first linter
+	// whose selectors match it. Used by Run; defaults to DefaultLinters.
+	Linters []Linter
+
+	// FS is the file system to lint. When set, paths are slash-separated paths
+	// within FS, as accepted by fs.ValidPath, and violations are reported
+	// relative to the root of FS. When nil, the local file system is used.
+	// See NewMemFS and OpenArchive.
+	FS fs.FS
+
+	// Format is the format used to report violations. Defaults to FormatText.
+	Format Format
+
+	// Stdout is where violations are reported. Defaults to os.Stdout.
+	Stdout io.Writer
+
+	// Reporter renders the result. When nil, a reporter for Format writing to
+	// Stdout is used.
+	Reporter Reporter
+
+	// ListSuppressions prints an audit listing of every suppression comment
+	// instead of the violations report. Violations do not fail the run.
+	ListSuppressions bool
+
+	// Baseline is the path of a baseline file. Violations present in the
+	// baseline are reported as warnings and do not fail the run.
+	Baseline string
+
+	// Workers is the number of files that are read and parsed concurrently.
+	// Defaults to runtime.GOMAXPROCS.
+	Workers int
+
+	// Exclude are globs of files and directories to skip, relative to each
+	// path being linted.
+	Exclude []string
+
+	// Include are globs of files to lint, relative to each path being linted.
+	// When empty, every file matching the linter's selectors is linted.
+	Include []string
+
+	// NoDefaultExcludes disables skipping the DefaultExcludes directories.
+	NoDefaultExcludes bool
+
+	// Gitignore skips files and directories ignored by .gitignore files found
+	// while walking.
+	Gitignore bool
+
+	// FollowSymlinks follows symlinks found while walking. Each directory is
+	// walked at most once, so symlink loops terminate. By default symlinks are
+	// skipped.
+	FollowSymlinks bool
+
+	// AllowParseErrors reports files that could not be read or parsed without
+	// failing the run.
+	AllowParseErrors bool
+
+	// FailOn is the lowest severity that fails the run. Violations with a
+	// lower severity are reported but do not fail the run. Defaults to
+	// SeverityError.
+	FailOn Severity
+
+	// Config is the project configuration. When nil, every rule is enabled with
+	// its default settings.
+	Config *Config
+
+	// WriteBaseline is the path to write a baseline of all current violations
+	// to. When set, violations do not fail the run.
+	WriteBaseline string
+
+	// Since is a git ref. When set, only files changed since the merge base of
+	// Since and HEAD are linted, and only violations on added or modified lines
+	// are reported. The git repository containing the first path is used.
+	Since string
+
+	// SinceWholeFile reports every violation in the files changed since Since,
+	// not only those on added or modified lines.
+	SinceWholeFile bool
+
+	// Fix applies the fixes of all violations that have one and writes the
+	// changed files. Fixed violations are reported in Result.Fixed and do not
+	// fail the run.
+	Fix bool
+
+	// Diff prints the changes Fix would make as a unified diff instead of the
+	// violations report. No files are changed.
+	Diff bool
+
+	// Cache stores results on disk so that unchanged files are not parsed
+	// again. When nil, nothing is cached.
+	Cache *Cache
+
+	// changes are the files and lines changed since Since, set by Run.
+	changes *changeSet
+
+	// baseline counts the entries of the Baseline file that have not matched
+	// a violation yet, set by Run.
+	baseline baselineCounts
+
+	// root is the directory fingerprints are relative to, set by Run. See
+	// baselineRoot.
+	root string
+
+	// onFile, if set, is called with the result of each file in walk order as
+	// soon as it has been linted, so reports can be written while the run is
+	// still in progress.
+	onFile func(res *Result) error
+}
+
+// Result is the outcome of a linter run.
+type Result struct {
+	// Violations are the violations found, in the order the files were read.
+	// Violations that are suppressed by a comment are not included.
+	Violations []*ViolationInstance
+
+	// Files are the paths of every file that was inspected.
+	Files []string
+
+	// Suppressions are all suppression comments found in the files.
+	Suppressions []*Suppression
+
+	// Suppressed are the violations that were suppressed by a suppression
+	// comment.
+	Suppressed []*ViolationInstance
+
+	// Diagnostics are the files that could not be read or parsed.
+	Diagnostics []*Diagnostic
+
+	// Fixed are the violations that were fixed by RunLinters with
+	// Options.Fix.
+	Fixed []*ViolationInstance
+
+	// Stats summarizes the run.
+	Stats Stats
+
+	// rules are the rules that were enabled for the run, set by Run. Results
+	// built by other means are assumed to have used every registered rule.
+	rules []Rule
+}
+
+// merge appends the files, violations and suppressions of other to r.
+func (r *Result) merge(other *Result) {
+	r.Violations = append(r.Violations, other.Violations...)
+	r.Files = append(r.Files, other.Files...)
+	r.Suppressions = append(r.Suppressions, other.Suppressions...)
+	r.Suppressed = append(r.Suppressed, other.Suppressed...)
+	r.Diagnostics = append(r.Diagnostics, other.Diagnostics...)
+	r.Stats.CacheHits += other.Stats.CacheHits
+}
+
+// Stats summarizes a linter run.
+type Stats struct {
+	// FilesScanned is the number of files that were inspected.
+	FilesScanned int
+
+	// Violations is the number of violations, including baselined ones.
+	Violations int
+
+	// Baselined is the number of violations present in the baseline.
+	Baselined int
+
+	// Suppressed is the number of violations suppressed by a comment.
+	Suppressed int
+
+	// ParseErrors is the number of diagnostics, that is files that could not
+	// be read or parsed.
+	ParseErrors int
+
+	// CacheHits is the number of files whose results were read from the cache.
+	CacheHits int
+
+	// Fixed is the number of violations that were fixed.
+	Fixed int
+
+	// Duration is how long the run took.
+	Duration time.Duration
+}
+
+// Reporter renders the result of a linter run.
+type Reporter interface {
+	Report(res *Result) error
+}
+
+// ReporterFunc adapts a function to the Reporter interface.
+type ReporterFunc func(res *Result) error
+
+// Report calls f(res).
+func (f ReporterFunc) Report(res *Result) error { return f(res) }
+
+// NewReporter returns a Reporter that writes the result to w in the given
+// format.
+func NewReporter(format Format, w io.Writer) (Reporter, error) {
+	if format != "" && !validFormat(format) {
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+	return ReporterFunc(func(res *Result) error {
+		return report(w, format, res)
+	}), nil
+}
+
+// DefaultLinters returns a new instance of every built-in linter.
+func DefaultLinters() []Linter {
+	return []Linter{&TerraformLinter{}, &GitHubActionLinter{}}
+}
+
+// Run lints opts.Paths and returns the result without printing anything.
+// Suppression comments and the baseline, if any, have already been applied to
+// the result. The returned error is only set if the run could not complete;
+// violations and files that could not be parsed are reported in the result.
+func Run(ctx context.Context, opts *Options) (*Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	start := time.Now()
+
+	linters := opts.Linters
+	if len(linters) == 0 {
+		linters = DefaultLinters()
+	}
+	for _, linter := range linters {
+		if c, ok := linter.(configurable); ok {
+			c.configure(opts.Config)
+		}
+	}
+
+	if opts.Since != "" {
+		if opts.FS != nil {
+			return nil, fmt.Errorf("Since cannot be combined with FS")
+		}
+		dir := "."
+		if len(opts.Paths) > 0 {
+			dir = opts.Paths[0]
+		}
+		changes, err := gitChanges(ctx, dir, opts.Since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find changes since %q: %w", opts.Since, err)
+		}
+		withChanges := *opts
+		withChanges.changes = changes
+		opts = &withChanges
+	}
+
+	root, err := baselineRoot(opts)
+	if err != nil {
+		return nil, err
+	}
+	withBaseline := *opts
+	withBaseline.root = root
+	if opts.Baseline != "" {
+		baseline, err := readBaseline(opts.Baseline)
+		if err != nil {
+			return nil, err
+		}
+		withBaseline.baseline = baseline.counts()
+	}
+	opts = &withBaseline
+
+	paths := opts.Paths
+	if len(paths) == 0 && opts.FS != nil {
+		paths = []string{"."}
+	}
+
+	// Process each provided path looking for violations
+	res, err := lint(ctx, paths, linters, opts)
+	if err != nil {
+		return nil, fmt.Errorf("error linting files: %w", err)
+	}
+	res.rules = enabledRules(opts.Config)
+
+	res.Stats = Stats{
+		FilesScanned: len(res.Files),
+		Violations:   len(res.Violations),
+		Suppressed:   len(res.Suppressed),
+		ParseErrors:  len(res.Diagnostics),
+		CacheHits:    res.Stats.CacheHits,
+		Duration:     time.Since(start),
+	}
+	for _, v := range res.Violations {
+		if v.Baselined {
+			res.Stats.Baselined++
+		}
+	}
+	return res, nil
+}
+
 // RunLinter run executes the linter for a set of files.
 func RunLinter(ctx context.Context, paths []string, linter Linter) error {
-	var violations []*ViolationInstance
-	// Process each provided path looking for violations
-	for _, path := range paths {
-		instances, err := lint(path, linter)
+	return RunLinterWithOptions(ctx, paths, linter, nil)
+}
+
+// RunLinterWithOptions executes the linter for a set of files with the given
+// options. A nil opts behaves like RunLinter.
+func RunLinterWithOptions(ctx context.Context, paths []string, linter Linter, opts *Options) error {
+	return RunLinters(ctx, paths, []Linter{linter}, opts)
+}
+
+// RunLinters executes several linters over a set of files in a single walk and
+// reports the result. Each file is handled by the first linter whose selectors
+// match it. It returns an error if violations were found or, unless
+// AllowParseErrors is set, if any file could not be linted.
+func RunLinters(ctx context.Context, paths []string, linters []Linter, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	stdout := opts.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
+	runOpts := *opts
+	runOpts.Paths = paths
+	runOpts.Linters = linters
+	if opts.WriteBaseline != "" || opts.ListSuppressions {
+		// The baseline does not affect either listing.
+		runOpts.Baseline = ""
+	}
+
+	// NDJSON records are streamed while the files are linted, unless the
+	// report is replaced or fixes change the violations after the run.
+	var stream *ndjsonWriter
+	if opts.Format == FormatNDJSON && opts.Reporter == nil && opts.WriteBaseline == "" &&
+		!opts.ListSuppressions && !opts.Fix && !opts.Diff {
+		stream = newNDJSONWriter(stdout)
+		if err := stream.begin(); err != nil {
+			return fmt.Errorf("error reporting violations: %w", err)
+		}
+		runOpts.onFile = stream.records
+	}
+
+	res, err := Run(ctx, &runOpts)
+	if err != nil {
+		return err
+	}
+
+	if opts.ListSuppressions {
+		if err := writeSuppressionAudit(stdout, res.Suppressions); err != nil {
+			return fmt.Errorf("error reporting suppressions: %w", err)
+		}
+		return nil
+	}
+
+	if opts.WriteBaseline != "" {
+		root, err := baselineRoot(&runOpts)
 		if err != nil {
-			return fmt.Errorf("error linting files: %w", err)
+			return err
+		}
+		if err := writeBaselineFile(opts.WriteBaseline, res.Violations, root); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// Files with conflicting fixes are left unchanged and their violations
+	// are reported as usual.
+	var fixErr error
+	if opts.Diff || opts.Fix {
+		if opts.Fix && opts.FS != nil {
+			return fmt.Errorf("Fix cannot be combined with FS")
+		}
+		fixErr = fixFiles(res, opts, stdout)
+		if opts.Diff {
+			return fixErr
 		}
-		violations = append(violations, instances...)
 	}
-	for _, instance := range violations {
-		fmt.Printf("%q detected at [%s:%d]\n", instance.ViolationType, instance.Path, instance.Line)
+
+	reporter := opts.Reporter
+	if stream != nil {
+		reporter = ReporterFunc(stream.end)
+	}
+	if reporter == nil {
+		if reporter, err = NewReporter(opts.Format, stdout); err != nil {
+			return fmt.Errorf("error reporting violations: %w", err)
+		}
 	}
-	if len(violations) != 0 {
-		return fmt.Errorf("found %d violation(s)", len(violations))
+	if err := reporter.Report(res); err != nil {
+		return fmt.Errorf("error reporting violations: %w", err)
 	}
+	return errors.Join(fixErr, res.Err(opts))
+}
 
-	return nil
+// Err returns an error describing why the run failed, or nil if it passed.
+// The run fails with a *ViolationsError if any violation that is not in the
+// baseline is at least as severe as opts.FailOn, and with a *LintFailedError
+// if any file could not be linted, unless opts.AllowParseErrors is set.
+func (r *Result) Err(opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	failOn := opts.FailOn
+	if failOn == "" {
+		failOn = SeverityError
+	}
+
+	var count int
+	for _, v := range r.Violations {
+		if !v.Baselined && v.Severity.AtLeast(failOn) {
+			count++
+		}
+	}
+
+	var merr error
+	if count != 0 {
+		merr = errors.Join(merr, &ViolationsError{Count: count})
+	}
+	if r.Stats.ParseErrors != 0 && !opts.AllowParseErrors {
+		merr = errors.Join(merr, &LintFailedError{Count: r.Stats.ParseErrors})
+	}
+	return merr
+}
+
+// ViolationsError is returned when a run finds violations that fail it.
+type ViolationsError struct {
+	// Count is the number of violations that failed the run.
+	Count int
+}
+
+func (e *ViolationsError) Error() string {
+	return fmt.Sprintf("found %d violation(s)", e.Count)
+}
+
+// LintFailedError is returned when files could not be read or parsed.
+type LintFailedError struct {
+	// Count is the number of files that could not be linted.
+	Count int
+}
+
+func (e *LintFailedError) Error() string {
+	return fmt.Sprintf("failed to lint %d file(s)", e.Count)
 }
 
-func lint(path string, linter Linter) ([]*ViolationInstance, error) {
-	isDir, err := isDirectory(path)
+// writeBaselineFile writes a baseline of the violations, with paths relative to
+// root, to path.
+func writeBaselineFile(path string, violations []*ViolationInstance, root string) (retErr error) {
+	f, err := os.Create(path)
 	if err != nil {
-		return nil, fmt.Errorf("error reading file at path %q: %w", path, err)
+		return fmt.Errorf("failed to create baseline: %w", err)
 	}
-	instances := []*ViolationInstance{}
-	if isDir {
-		files, err := os.ReadDir(path)
-		if err != nil {
-			return nil, fmt.Errorf("error reading directory at path %q: %w", path, err)
+	defer func() {
+		if err := f.Close(); err != nil && retErr == nil {
+			retErr = fmt.Errorf("failed to close baseline: %w", err)
 		}
-		for _, file := range files {
-			next := filepath.Join(path, file.Name())
-			results, err := lint(next, linter)
-			if err != nil {
-				return nil, err
+	}()
+	return writeBaseline(f, violations, root)
+}
+
+// report writes the result to w in the given format.
+func report(w io.Writer, format Format, res *Result) error {
+	switch format {
+	case FormatText, "":
+		for _, instance := range res.Violations {
+			fmt.Fprintf(w, "%q detected at [%s]", instance.ViolationType, textLocation(instance))
+			if instance.Object != "" {
+				fmt.Fprintf(w, " in %s", instance.Object)
 			}
-			if results != nil {
-				instances = append(instances, results...)
+			if instance.Baselined {
+				fmt.Fprint(w, " (baselined)")
 			}
+			fmt.Fprintln(w)
 		}
-	} else {
-		for _, sel := range linter.Selectors() {
-			if strings.HasSuffix(path, sel) {
-				content, err := os.ReadFile(path)
-				if err != nil {
-					return nil, fmt.Errorf("error reading file: %w", err)
-				}
-				results, err := linter.FindViolations(content, path)
-				if err != nil {
-					return nil, fmt.Errorf("error searching for violations %w", err)
-				}
-				instances = append(instances, results...)
-			}
+		for _, d := range res.Diagnostics {
+			fmt.Fprintf(w, "error linting [%s]: %s\n", diagnosticLocation(d), d.Message)
 		}
+		return nil
+	case FormatSARIF:
+		return writeSARIF(w, res)
+	case FormatGitHub:
+		return writeGitHubAnnotations(w, res)
+	case FormatJSON:
+		return writeJSON(w, res)
+	case FormatNDJSON:
+		return writeNDJSON(w, res)
+	case FormatJUnit:
+		return writeJUnit(w, res)
+	case FormatCodeQuality:
+		return writeCodeQuality(w, res)
+	default:
+		return fmt.Errorf("unsupported format %q", format)
 	}
-	return instances, nil
 }
 
-func isDirectory(path string) (bool, error) {
-	fileInfo, err := os.Stat(path)
-	if err != nil {
-		return false, fmt.Errorf("error reading file information %w", err)
+// textLocation formats the start of a violation as path:line[:column].
+func textLocation(v *ViolationInstance) string {
+	if v.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", v.Path, v.Line, v.Column)
 	}
-	return fileInfo.IsDir(), err
+	return fmt.Sprintf("%s:%d", v.Path, v.Line)
 }
@@ -0,0 +1,145 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"main.tf":                   testLocalExec,
+		"broken.tf":                 "a = ~b\n",
+		".github/workflows/ci.yaml": "jobs:\n  plan:\n    steps:\n      - uses: 'hashicorp/setup-terraform@v2'\n",
+	})
+
+	var stdout bytes.Buffer
+	res, err := Run(context.Background(), &Options{
+		Paths:  []string{dir},
+		Stdout: &stdout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("expected nothing to be printed, got %q", stdout.String())
+	}
+
+	want := Stats{FilesScanned: 3, Violations: 4, ParseErrors: 1}
+	if diff := cmp.Diff(want, res.Stats, cmpopts.IgnoreFields(Stats{}, "Duration")); diff != "" {
+		t.Errorf("stats (-want,+got):\n%s", diff)
+	}
+	var rules []string
+	for _, v := range res.Violations {
+		rules = append(rules, v.RuleID)
+	}
+	if diff := cmp.Diff([]string{"SST003", "SST006", "SST009", "SST001"}, rules); diff != "" {
+		t.Errorf("rules (-want,+got):\n%s", diff)
+	}
+	if got, want := res.Diagnostics[0].Path, filepath.Join(dir, "broken.tf"); got != want {
+		t.Errorf("expected diagnostic for %q, got %q", want, got)
+	}
+
+	if err := res.Err(nil); err == nil || !strings.Contains(err.Error(), "failed to lint 1 file(s)") {
+		t.Errorf("expected parse failure, got %v", err)
+	}
+	if err := res.Err(&Options{AllowParseErrors: true}); err == nil || strings.Contains(err.Error(), "failed to lint") {
+		t.Errorf("expected only violations, got %v", err)
+	}
+}
+
+func TestRunLinter_Reporter(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"main.tf": testLocalExec})
+
+	var got *Result
+	err := RunLinterWithOptions(context.Background(), []string{dir}, &TerraformLinter{}, &Options{
+		Stdout: &bytes.Buffer{},
+		Reporter: ReporterFunc(func(res *Result) error {
+			got = res
+			return nil
+		}),
+	})
+	if err == nil || err.Error() != "found 3 violation(s)" {
+		t.Errorf("expected violation error, got %v", err)
+	}
+	if got == nil || len(got.Violations) != testLocalExecViolations {
+		t.Fatalf("expected the reporter to receive %d violations, got %+v", testLocalExecViolations, got)
+	}
+}
+
+func TestResult_Err(t *testing.T) {
+	t.Parallel()
+
+	res := &Result{
+		Violations: []*ViolationInstance{
+			{RuleID: "SST001", Severity: SeverityWarning},
+			{RuleID: "SST002", Severity: SeverityInfo},
+			{RuleID: "SST003", Severity: SeverityError, Baselined: true},
+		},
+	}
+
+	cases := []struct {
+		name   string
+		failOn Severity
+		expect int
+	}{
+		{
+			name:   "default",
+			expect: 0,
+		},
+		{
+			name:   "warning",
+			failOn: SeverityWarning,
+			expect: 1,
+		},
+		{
+			name:   "info",
+			failOn: SeverityInfo,
+			expect: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := res.Err(&Options{FailOn: tc.failOn})
+			var got int
+			var verr *ViolationsError
+			if errors.As(err, &verr) {
+				got = verr.Count
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expect {
+				t.Errorf("expected %d failing violations, got %d", tc.expect, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,331 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+)
+
+// Options of the provider-source rule.
+const (
+	optionAllow = "allow"
+	optionDeny  = "deny"
+)
+
+const (
+	// defaultProviderHost is the registry of provider source addresses that do
+	// not name one.
+	defaultProviderHost = "registry.terraform.io"
+
+	// defaultProviderNamespace is the namespace Terraform assumes for providers
+	// that are not in required_providers or have no source.
+	defaultProviderNamespace = "hashicorp"
+
+	// builtinProvider is the local name of the provider built into Terraform,
+	// used by terraform_data and terraform_remote_state.
+	builtinProvider = "terraform"
+)
+
+// defaultDeniedProviders are providers that run commands or read and write
+// files on the machine running Terraform.
+var defaultDeniedProviders = []string{"hashicorp/external", "hashicorp/local", "hashicorp/null"}
+
+// providerSchema selects the provider meta-argument of a resource or data
+// source.
+var providerSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{{Name: "provider"}},
+}
+
+// providerRule checks the source address of every provider used by a file
+// against allow and deny lists.
+type providerRule struct {
+	info *RuleInfo
+}
+
+func (r *providerRule) Info() *RuleInfo { return r.info }
+
+// CheckTerraform checks the providers in required_providers and the providers
+// used implicitly by resources, data sources and provider blocks that are not
+// in required_providers. Terraform merges the required_providers of every file
+// in a module, so implicit references are skipped if any file of the module
+// declares them, and otherwise use the hashicorp namespace as Terraform does.
+func (r *providerRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	allow := pass.Option(optionAllow, nil)
+	deny := pass.Option(optionDeny, defaultDeniedProviders)
+
+	check := func(source string, rng hcl.Range, object, usedBy string) {
+		addr, ok := providerAddress(source)
+		if !ok {
+			return
+		}
+		var problem string
+		switch {
+		case matchAnyProvider(deny, addr):
+			problem = "is on the deny list"
+		case len(allow) > 0 && !matchAnyProvider(allow, addr):
+			problem = "is not on the allow list"
+		default:
+			return
+		}
+		msg := fmt.Sprintf("Provider %q %s.", addr, problem)
+		if usedBy != "" {
+			msg = fmt.Sprintf("Provider %q, used implicitly by %s, %s.", addr, usedBy, problem)
+		}
+		s := hclSpan(rng)
+		pass.Report(&ViolationInstance{
+			Line:      s.startLine,
+			Column:    s.startColumn,
+			EndLine:   s.endLine,
+			EndColumn: s.endColumn,
+			Object:    object,
+			Message:   msg,
+		})
+	}
+
+	declared := make(map[string]bool)
+	for _, name := range file.moduleProviders {
+		declared[name] = true
+	}
+	file.Inspect(func(b *Block) bool {
+		if b.isRequiredProviders() {
+			for _, req := range providerRequirements(b) {
+				declared[req.name] = true
+				check(req.source, req.rng, b.Address(), "")
+			}
+		}
+		return true
+	})
+
+	// Implicit references are reported once per provider, at the first block
+	// that uses it, rather than for every resource of that provider.
+	file.Inspect(func(b *Block) bool {
+		name, rng, usedBy, ok := implicitProvider(b)
+		if ok && !declared[name] && name != builtinProvider {
+			declared[name] = true
+			check(name, rng, b.Address(), usedBy)
+		}
+		return true
+	})
+}
+
+// isRequiredProviders returns true if b is a required_providers block.
+func (b *Block) isRequiredProviders() bool {
+	return b.Type == "required_providers" && b.Parent != nil && b.Parent.Type == "terraform"
+}
+
+// moduleProviders loads the local names of the providers required by each
+// Terraform module, that is each directory, at most once.
+type moduleProviders struct {
+	fsys fileSystem
+
+	mu   sync.Mutex
+	dirs map[string]*moduleProvidersDir
+}
+
+type moduleProvidersDir struct {
+	once  sync.Once
+	names []string
+}
+
+func newModuleProviders(fsys fileSystem) *moduleProviders {
+	return &moduleProviders{fsys: fsys, dirs: make(map[string]*moduleProvidersDir)}
+}
+
+// names returns the sorted local names of the providers in the
+// required_providers blocks of every Terraform file in dir. Files that cannot
+// be read or parsed are skipped.
+func (m *moduleProviders) names(dir string) []string {
+	m.mu.Lock()
+	d, ok := m.dirs[dir]
+	if !ok {
+		d = &moduleProvidersDir{}
+		m.dirs[dir] = d
+	}
+	m.mu.Unlock()
+
+	d.once.Do(func() {
+		entries, err := m.fsys.readDir(dir)
+		if err != nil {
+			return
+		}
+		seen := make(map[string]bool)
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || (!strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, terraformJSONSuffix)) {
+				continue
+			}
+			p := m.fsys.join(dir, name)
+			content, err := m.fsys.readFile(p)
+			if err != nil {
+				continue
+			}
+			file, err := parseTerraform(content, p)
+			if err != nil {
+				continue
+			}
+			file.Inspect(func(b *Block) bool {
+				if b.isRequiredProviders() {
+					for _, req := range providerRequirements(b) {
+						if !seen[req.name] {
+							seen[req.name] = true
+							d.names = append(d.names, req.name)
+						}
+					}
+				}
+				return true
+			})
+		}
+		sort.Strings(d.names)
+	})
+	return d.names
+}
+
+// providerRequirement is an entry of a required_providers block.
+type providerRequirement struct {
+	// name is the local name of the provider.
+	name string
+
+	// source is the source address, or the local name if there is none.
+	source string
+
+	// rng is the range of the local name.
+	rng hcl.Range
+}
+
+// providerRequirements returns the entries of a required_providers block in
+// source order. Entries are either an object with a source attribute or, in
+// older configurations, a version constraint string.
+func providerRequirements(b *Block) []*providerRequirement {
+	attrs, _ := b.Body.JustAttributes()
+	reqs := make([]*providerRequirement, 0, len(attrs))
+	for name, attr := range attrs {
+		req := &providerRequirement{name: name, source: name, rng: attr.NameRange}
+		pairs, diags := hcl.ExprMap(attr.Expr)
+		if !diags.HasErrors() {
+			for _, pair := range pairs {
+				if key, ok := exprString(pair.Key); ok && key == "source" {
+					if source, ok := exprString(pair.Value); ok {
+						req.source = source
+					}
+				}
+			}
+		}
+		reqs = append(reqs, req)
+	}
+	sort.Slice(reqs, func(i, j int) bool {
+		return reqs[i].rng.Start.Byte < reqs[j].rng.Start.Byte
+	})
+	return reqs
+}
+
+// implicitProvider returns the local name of the provider used by a resource,
+// data source or provider block, with the range to report and a description
+// of the reference. It returns false for other blocks.
+func implicitProvider(b *Block) (name string, rng hcl.Range, usedBy string, ok bool) {
+	switch b.Type {
+	case "provider":
+		if len(b.Labels) != 1 {
+			return "", hcl.Range{}, "", false
+		}
+		return b.Labels[0], b.reportRange(), fmt.Sprintf("provider block %q", b.Labels[0]), true
+
+	case "resource", "data", "ephemeral":
+		if len(b.Labels) != 2 || (b.Parent != nil && b.Parent.Type != "check") {
+			return "", hcl.Range{}, "", false
+		}
+		typ := b.Labels[0]
+		usedBy = fmt.Sprintf("%q", typ)
+
+		// The provider meta-argument selects the provider explicitly, for
+		// example provider = google.europe.
+		content, _, _ := b.Body.PartialContent(providerSchema)
+		if attr, ok := content.Attributes["provider"]; ok {
+			if traversal, diags := hcl.AbsTraversalForExpr(attr.Expr); !diags.HasErrors() {
+				return traversal.RootName(), attr.Expr.Range(), usedBy, true
+			}
+		}
+
+		// Otherwise the provider is the prefix of the type, for example "null"
+		// for null_resource.
+		name, _, _ = strings.Cut(typ, "_")
+		return name, b.reportRange(), usedBy, true
+	}
+	return "", hcl.Range{}, "", false
+}
+
+// providerAddress normalizes a provider source address into its
+// hostname/namespace/type form, filling in the default registry and namespace
+// as Terraform does. It returns false if source is not a valid address.
+func providerAddress(source string) (string, bool) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(source)), "/")
+	for _, p := range parts {
+		if p == "" {
+			return "", false
+		}
+	}
+	switch len(parts) {
+	case 1:
+		return path.Join(defaultProviderHost, defaultProviderNamespace, parts[0]), true
+	case 2:
+		return path.Join(defaultProviderHost, parts[0], parts[1]), true
+	case 3:
+		return path.Join(parts...), true
+	default:
+		return "", false
+	}
+}
+
+// matchAnyProvider returns true if the normalized provider address addr
+// matches one of patterns. Patterns may use * wildcards within each part. A
+// pattern without a hostname, such as "hashicorp/null", matches the provider
+// from any registry and a pattern with only a type uses the hashicorp
+// namespace.
+func matchAnyProvider(patterns []string, addr string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		name := addr
+		switch strings.Count(pattern, "/") {
+		case 0:
+			pattern = defaultProviderNamespace + "/" + pattern
+			fallthrough
+		case 1:
+			_, name, _ = strings.Cut(addr, "/")
+		}
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// exprString returns the value of an expression that is a keyword or a string
+// that does not depend on any variables.
+func exprString(expr hcl.Expression) (string, bool) {
+	if kw := hcl.ExprAsKeyword(expr); kw != "" {
+		return kw, true
+	}
+	var s string
+	if diags := gohcl.DecodeExpression(expr, nil, &s); diags.HasErrors() {
+		return "", false
+	}
+	return s, true
+}
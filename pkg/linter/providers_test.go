@@ -0,0 +1,235 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProviderRule(t *testing.T) {
+	t.Parallel()
+
+	options := func(opts map[string][]string) *Config {
+		return &Config{Rules: map[string]*RuleConfig{ruleIDProviderSource: {Options: opts}}}
+	}
+
+	cases := []struct {
+		name     string
+		filename string
+		content  string
+		cfg      *Config
+		expect   []string
+	}{
+		{
+			name:     "required providers",
+			filename: "versions.tf",
+			content: `terraform {
+  required_providers {
+    google   = { source = "hashicorp/google" }
+    null     = { source = "hashicorp/null", version = "~> 3.0" }
+    local    = "~> 2.0"
+    external = {
+      source = "registry.opentofu.org/hashicorp/external"
+    }
+  }
+}
+`,
+			expect: []string{
+				`4:5 terraform: Provider "registry.terraform.io/hashicorp/null" is on the deny list.`,
+				`5:5 terraform: Provider "registry.terraform.io/hashicorp/local" is on the deny list.`,
+				`6:5 terraform: Provider "registry.opentofu.org/hashicorp/external" is on the deny list.`,
+			},
+		},
+		{
+			name:     "implicit references",
+			filename: "main.tf",
+			content: `resource "null_resource" "echo" {}
+
+data "external" "script" {
+  program = ["./script.sh"]
+}
+
+resource "google_storage_bucket" "logs" {
+  provider = local.europe
+}
+
+resource "terraform_data" "replacement" {}
+
+provider "null" {}
+`,
+			expect: []string{
+				`1:10 null_resource.echo: Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+				`3:6 data.external.script: Provider "registry.terraform.io/hashicorp/external", used implicitly by "external", is on the deny list.`,
+				`8:14 google_storage_bucket.logs: Provider "registry.terraform.io/hashicorp/local", used implicitly by "google_storage_bucket", is on the deny list.`,
+			},
+		},
+		{
+			name:     "reported once per provider",
+			filename: "main.tf",
+			content: `provider "null" {}
+
+resource "null_resource" "a" {}
+
+resource "null_resource" "b" {}
+`,
+			expect: []string{
+				`1:10 provider.null: Provider "registry.terraform.io/hashicorp/null", used implicitly by provider block "null", is on the deny list.`,
+			},
+		},
+		{
+			name:     "declared providers are not implicit",
+			filename: "main.tf",
+			content: `terraform {
+  required_providers {
+    null = { source = "mycorp/null" }
+  }
+}
+
+resource "null_resource" "echo" {}
+`,
+		},
+		{
+			name:     "allow list",
+			filename: "main.tf",
+			content: `terraform {
+  required_providers {
+    google = { source = "hashicorp/google" }
+    thing  = { source = "registry.example.com/acme/thing" }
+    null   = { source = "hashicorp/null" }
+  }
+}
+
+resource "aws_instance" "web" {}
+`,
+			cfg: options(map[string][]string{
+				optionAllow: {"hashicorp/google", "registry.example.com/*/*", "null"},
+			}),
+			expect: []string{
+				`5:5 terraform: Provider "registry.terraform.io/hashicorp/null" is on the deny list.`,
+				`9:10 aws_instance.web: Provider "registry.terraform.io/hashicorp/aws", used implicitly by "aws_instance", is not on the allow list.`,
+			},
+		},
+		{
+			name:     "empty deny list",
+			filename: "main.tf",
+			content:  `resource "null_resource" "echo" {}`,
+			cfg:      options(map[string][]string{optionDeny: {}}),
+		},
+		{
+			name:     "json syntax",
+			filename: "main.tf.json",
+			content: `{
+  "terraform": {
+    "required_providers": {
+      "local": {"source": "hashicorp/local"}
+    }
+  },
+  "resource": {
+    "null_resource": {"echo": {}}
+  }
+}
+`,
+			expect: []string{
+				`4:7 terraform: Provider "registry.terraform.io/hashicorp/local" is on the deny list.`,
+				`8:31 null_resource.echo: Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := findRuleViolations(t, ruleIDProviderSource, tc.filename, tc.content, tc.cfg)
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("violations (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestProviderRule_Module(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Config{Rules: map[string]*RuleConfig{
+		ruleIDProviderSource: {Options: map[string][]string{optionAllow: {"cloudflare/cloudflare"}}},
+	}}
+	fsys := fstest.MapFS{
+		"versions.tf": {Data: []byte(`terraform {
+  required_providers {
+    cloudflare = { source = "cloudflare/cloudflare" }
+  }
+}
+`)},
+		"main.tf": {Data: []byte(`provider "cloudflare" {}
+
+resource "cloudflare_record" "x" {}
+`)},
+		// Other directories are separate modules.
+		"modules/dns/main.tf": {Data: []byte(`resource "cloudflare_record" "y" {}
+`)},
+	}
+
+	res, err := Run(context.Background(), &Options{
+		FS:      fsys,
+		Linters: []Linter{&TerraformLinter{}},
+		Config:  cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range res.Violations {
+		got = append(got, fmt.Sprintf("%s:%d:%d %s: %s", v.Path, v.Line, v.Column, v.Object, v.Message))
+	}
+	want := []string{
+		`modules/dns/main.tf:1:10 cloudflare_record.y: Provider "registry.terraform.io/hashicorp/cloudflare", used implicitly by "cloudflare_record", is not on the allow list.`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("violations (-want,+got):\n%s", diff)
+	}
+}
+
+func TestProviderAddress(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		source string
+		expect string
+		ok     bool
+	}{
+		{source: "null", expect: "registry.terraform.io/hashicorp/null", ok: true},
+		{source: "Hashicorp/Null", expect: "registry.terraform.io/hashicorp/null", ok: true},
+		{source: "registry.example.com/acme/thing", expect: "registry.example.com/acme/thing", ok: true},
+		{source: "a/b/c/d"},
+		{source: "hashicorp/"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.source, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := providerAddress(tc.source)
+			if got != tc.expect || ok != tc.ok {
+				t.Errorf("providerAddress(%q) = %q, %t, want %q, %t", tc.source, got, ok, tc.expect, tc.ok)
+			}
+		})
+	}
+}
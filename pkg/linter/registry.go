@@ -0,0 +1,191 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Rule is a single check. Rules are registered with Register and run by the
+// linter that parses the type of file the rule inspects. A rule must also
+// implement one of TerraformRule or WorkflowRule.
+type Rule interface {
+	// Info returns the metadata of the rule. It must return the same value on
+	// every call.
+	Info() *RuleInfo
+}
+
+// TerraformRule is a rule run against each Terraform file.
+type TerraformRule interface {
+	Rule
+
+	// CheckTerraform reports violations in file to pass.
+	CheckTerraform(pass *Pass, file *TerraformFile)
+}
+
+// WorkflowRule is a rule run against each GitHub Actions workflow file.
+type WorkflowRule interface {
+	Rule
+
+	// CheckWorkflow reports violations in workflow to pass.
+	CheckWorkflow(pass *Pass, workflow *Workflow)
+}
+
+// RuleInfo describes a rule.
+type RuleInfo struct {
+	// ID is the stable identifier of the rule, for example "SST001". IDs must
+	// be unique and should never change once released.
+	ID string
+
+	// Name is the short name of the rule, for example "local-exec". It is used
+	// as ViolationInstance.ViolationType.
+	Name string
+
+	// Description is a short, human readable description of the rule.
+	Description string
+
+	// DefaultSeverity is the severity reported for violations of the rule.
+	// Defaults to SeverityError.
+	DefaultSeverity Severity
+
+	// Message is the human readable message attached to each violation.
+	Message string
+
+	// Remediation is a hint describing how to fix a violation.
+	Remediation string
+
+	// DocsURL links to the documentation of the rule.
+	DocsURL string
+
+	// Options are the names and descriptions of the options the rule accepts in
+	// the project configuration.
+	Options map[string]string
+}
+
+// Pass is handed to a rule for each file it checks. It gives the rule access
+// to its options and collects the violations it reports.
+type Pass struct {
+	// Path is the path of the file being checked.
+	Path string
+
+	info       *RuleInfo
+	cfg        *Config
+	violations []*ViolationInstance
+}
+
+// Option returns the value of a rule option from the project configuration,
+// or def if it is not set.
+func (p *Pass) Option(name string, def []string) []string {
+	return p.cfg.ruleOption(p.info.ID, name, def)
+}
+
+// Report records a violation. The rule only needs to set the location and
+// object of v; the path and any rule metadata left empty are filled in from
+// the pass.
+func (p *Pass) Report(v *ViolationInstance) {
+	v.Path = p.Path
+	v.ViolationType = p.info.Name
+	v.RuleID = p.info.ID
+	if v.Severity == "" {
+		v.Severity = p.info.DefaultSeverity
+	}
+	if v.Message == "" {
+		v.Message = p.info.Message
+	}
+	if v.Remediation == "" {
+		v.Remediation = p.info.Remediation
+	}
+	p.violations = append(p.violations, v)
+}
+
+// report records a violation at s within object.
+func (p *Pass) report(s span, object string) {
+	p.Report(&ViolationInstance{
+		Line:      s.startLine,
+		Column:    s.startColumn,
+		EndLine:   s.endLine,
+		EndColumn: s.endColumn,
+		Object:    object,
+	})
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     []Rule
+)
+
+// Register makes a rule available to the linters. It is intended to be called
+// from an init function. Register panics if the rule's ID or name is empty or
+// already registered, or if the rule does not implement one of TerraformRule
+// or WorkflowRule.
+func Register(rule Rule) {
+	info := rule.Info()
+	if info == nil || info.ID == "" || info.Name == "" {
+		panic("linter: Register rule without ID or name")
+	}
+	switch rule.(type) {
+	case TerraformRule, WorkflowRule:
+	default:
+		panic(fmt.Sprintf("linter: Register rule %s does not implement TerraformRule or WorkflowRule", info.ID))
+	}
+	if info.DefaultSeverity == "" {
+		info.DefaultSeverity = SeverityError
+	}
+	if !validSeverity(info.DefaultSeverity) {
+		panic(fmt.Sprintf("linter: Register rule %s has invalid severity %q", info.ID, info.DefaultSeverity))
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for _, r := range registry {
+		existing := r.Info()
+		if strings.EqualFold(existing.ID, info.ID) || existing.Name == info.Name {
+			panic(fmt.Sprintf("linter: Register called twice for rule %s (%s)", info.ID, info.Name))
+		}
+	}
+	registry = append(registry, rule)
+}
+
+// Rules returns every registered rule, in registration order.
+func Rules() []Rule {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	return append([]Rule(nil), registry...)
+}
+
+// LookupRule returns the registered rule with the given name or ID, or nil if
+// there is no such rule.
+func LookupRule(nameOrID string) Rule {
+	for _, r := range Rules() {
+		if info := r.Info(); info.Name == nameOrID || strings.EqualFold(info.ID, nameOrID) {
+			return r
+		}
+	}
+	return nil
+}
+
+// enabledRules returns the rules enabled by cfg.
+func enabledRules(cfg *Config) []Rule {
+	all := Rules()
+	rules := all[:0]
+	for _, r := range all {
+		if cfg.ruleEnabled(r.Info().ID) {
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
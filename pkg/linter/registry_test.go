@@ -0,0 +1,183 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// forbiddenResourceRule is an organization specific rule, registered the same
+// way a rule from another module would be.
+type forbiddenResourceRule struct{}
+
+var forbiddenResourceInfo = &RuleInfo{
+	ID:          "TEST001",
+	Name:        "forbidden-resource",
+	Description: "Flags the forbidden_resource type.",
+	Message:     "forbidden_resource is not allowed.",
+}
+
+func (forbiddenResourceRule) Info() *RuleInfo { return forbiddenResourceInfo }
+
+func (forbiddenResourceRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	for _, b := range file.Blocks {
+		if b.Type == "resource" && len(b.Labels) == 2 && b.Labels[0] == "forbidden_resource" {
+			pass.report(hclSpan(b.LabelRanges[0]), b.Address())
+		}
+	}
+}
+
+// registerTestRule registers rule for the duration of the test. Tests using it
+// cannot run in parallel, so that no other test sees the rule.
+func registerTestRule(t *testing.T, rule Rule) {
+	t.Helper()
+
+	Register(rule)
+	t.Cleanup(func() {
+		registryLock.Lock()
+		defer registryLock.Unlock()
+		registry = slices.DeleteFunc(registry, func(r Rule) bool { return r == rule })
+	})
+}
+
+// newViolation builds a violation of the rule with the given name, filling in
+// the rule metadata.
+func newViolation(name, path string, s span, object string) *ViolationInstance {
+	v := &ViolationInstance{
+		ViolationType: name,
+		Path:          path,
+		Line:          s.startLine,
+		Column:        s.startColumn,
+		EndLine:       s.endLine,
+		EndColumn:     s.endColumn,
+		Object:        object,
+	}
+	if r := LookupRule(name); r != nil {
+		pass := &Pass{Path: path, info: r.Info()}
+		pass.Report(v)
+	}
+	return v
+}
+
+func TestRegisteredRule(t *testing.T) {
+	registerTestRule(t, forbiddenResourceRule{})
+
+	content := []byte(`resource "forbidden_resource" "x" {
+  provisioner "local-exec" {
+    command = "true"
+  }
+}
+`)
+
+	cases := []struct {
+		name   string
+		cfg    *Config
+		expect []string
+	}{
+		{
+			name:   "all rules",
+			expect: []string{"TEST001", "SST001"},
+		},
+		{
+			name: "disabled",
+			cfg: &Config{Rules: map[string]*RuleConfig{
+				"TEST001": {Enabled: new(bool)},
+			}},
+			expect: []string{"SST001"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			l := &TerraformLinter{}
+			l.configure(tc.cfg)
+			violations, err := l.FindViolations(content, "main.tf")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]string, 0, len(violations))
+			for _, v := range violations {
+				got = append(got, v.RuleID)
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("rule IDs (-want,+got):\n%s", diff)
+			}
+			for _, v := range violations {
+				if v.Severity != SeverityError || v.Path != "main.tf" {
+					t.Errorf("unexpected violation metadata: %+v", v)
+				}
+			}
+			if v := violations[len(violations)-1]; v.Object != "forbidden_resource.x" {
+				t.Errorf("unexpected violation metadata: %+v", v)
+			}
+		})
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		rule      Rule
+		wantPanic string
+	}{
+		{
+			name:      "duplicate id",
+			rule:      &provisionerRule{info: &RuleInfo{ID: "sst001", Name: "other"}},
+			wantPanic: "called twice",
+		},
+		{
+			name:      "duplicate name",
+			rule:      &provisionerRule{info: &RuleInfo{ID: "ORG001", Name: "local-exec"}},
+			wantPanic: "called twice",
+		},
+		{
+			name:      "missing id",
+			rule:      &provisionerRule{info: &RuleInfo{Name: "other"}},
+			wantPanic: "without ID",
+		},
+		{
+			name:      "no check",
+			rule:      infoOnlyRule{},
+			wantPanic: "does not implement",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				r := recover()
+				msg, _ := r.(string)
+				if !strings.Contains(msg, tc.wantPanic) {
+					t.Errorf("expected panic containing %q, got %v", tc.wantPanic, r)
+				}
+			}()
+			Register(tc.rule)
+		})
+	}
+}
+
+type infoOnlyRule struct{}
+
+func (infoOnlyRule) Info() *RuleInfo { return &RuleInfo{ID: "ORG002", Name: "info-only"} }
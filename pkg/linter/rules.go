@@ -0,0 +1,245 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity is how serious a violation is.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Severities is the list of supported severities, most severe first.
+var Severities = []Severity{SeverityError, SeverityWarning, SeverityInfo}
+
+func validSeverity(s Severity) bool {
+	for _, sev := range Severities {
+		if s == sev {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSeverity converts a string into a Severity, returning an error if the
+// severity is not supported.
+func ParseSeverity(s string) (Severity, error) {
+	if sev := Severity(s); validSeverity(sev) {
+		return sev, nil
+	}
+	return "", fmt.Errorf("unsupported severity %q, must be one of %q", s, Severities)
+}
+
+// AtLeast returns true if s is as severe as threshold or more. Unknown
+// severities are treated as errors.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return severityRank(s) >= severityRank(threshold)
+}
+
+func severityRank(s Severity) int {
+	switch s {
+	case SeverityInfo:
+		return 0
+	case SeverityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// Stable identifiers for each rule. These never change once released, even if
+// the rule's short name or behavior does.
+const (
+	ruleIDLocalExec           = "SST001"
+	ruleIDRemoteExec          = "SST002"
+	ruleIDSetupTerraform      = "SST003"
+	ruleIDFile                = "SST004"
+	ruleIDConnection          = "SST005"
+	ruleIDProviderSource      = "SST006"
+	ruleIDExternalData        = "SST007"
+	ruleIDHTTPData            = "SST008"
+	ruleIDProvisionerResource = "SST009"
+)
+
+// rulesDocsURL documents the built-in rules.
+const rulesDocsURL = toolInformationURI + "#rules"
+
+func init() {
+	Register(&provisionerRule{
+		info: &RuleInfo{
+			ID:              ruleIDLocalExec,
+			Name:            tokenLocalExec,
+			Description:     "Terraform 'local-exec' provisioners run arbitrary commands on the machine running Terraform.",
+			DefaultSeverity: SeverityError,
+			Message:         `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+			Remediation:     "Remove the provisioner and move the command into a separate, reviewed build step.",
+			DocsURL:         rulesDocsURL,
+		},
+		provisioner: tokenLocalExec,
+	})
+	Register(&provisionerRule{
+		info: &RuleInfo{
+			ID:              ruleIDRemoteExec,
+			Name:            tokenRemoteExec,
+			Description:     "Terraform 'remote-exec' provisioners run arbitrary commands on remote hosts from the machine running Terraform.",
+			DefaultSeverity: SeverityError,
+			Message:         `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`,
+			Remediation:     "Remove the provisioner and configure the host with startup scripts or images instead.",
+			DocsURL:         rulesDocsURL,
+		},
+		provisioner: tokenRemoteExec,
+	})
+	Register(&setupTerraformRule{
+		info: &RuleInfo{
+			ID:              ruleIDSetupTerraform,
+			Name:            tokenSetupTerraform,
+			Description:     "Workflows should use 'abcxyz/secure-setup-terraform' instead of calling 'hashicorp/setup-terraform' directly.",
+			DefaultSeverity: SeverityError,
+			Message:         `Step uses "hashicorp/setup-terraform" directly.`,
+			Remediation:     "Use 'abcxyz/secure-setup-terraform' with the same inputs instead.",
+			DocsURL:         rulesDocsURL,
+			Options: map[string]string{
+				optionActions:     "Action references to flag. Defaults to hashicorp/setup-terraform.",
+				optionReplacement: "Action reference that fixes use instead. Defaults to abcxyz/secure-setup-terraform at the release of this tool; development builds offer no fix unless this is set.",
+			},
+		},
+	})
+	Register(&provisionerRule{
+		info: &RuleInfo{
+			ID:              ruleIDFile,
+			Name:            "file-provisioner",
+			Description:     "Terraform 'file' provisioners copy files from the machine running Terraform to remote hosts.",
+			DefaultSeverity: SeverityError,
+			Message:         `Provisioner "file" copies files from the machine running Terraform to remote hosts.`,
+			Remediation:     "Remove the provisioner and ship the files in images or startup scripts instead.",
+			DocsURL:         rulesDocsURL,
+		},
+		provisioner: tokenFile,
+	})
+	Register(&connectionRule{
+		info: &RuleInfo{
+			ID:              ruleIDConnection,
+			Name:            tokenConnection,
+			Description:     "Terraform 'connection' blocks open SSH or WinRM sessions from the machine running Terraform to remote hosts.",
+			DefaultSeverity: SeverityError,
+			Message:         "Connection block opens an SSH or WinRM session from the machine running Terraform.",
+			Remediation:     "Remove the connection and configure the host without connecting to it from Terraform.",
+			DocsURL:         rulesDocsURL,
+		},
+	})
+	Register(&providerRule{
+		info: &RuleInfo{
+			ID:              ruleIDProviderSource,
+			Name:            "provider-source",
+			Description:     "Terraform providers must be allowed by the provider allow and deny lists.",
+			DefaultSeverity: SeverityError,
+			Message:         "Provider is not allowed.",
+			Remediation:     "Remove the provider, or add it to the allow list of the rule after review.",
+			DocsURL:         rulesDocsURL,
+			Options: map[string]string{
+				optionAllow: "Provider source addresses to allow, such as hashicorp/google or registry.example.com/*/*. When set, every other provider is reported.",
+				optionDeny:  "Provider source addresses to report, even if allowed. Defaults to hashicorp/external, hashicorp/local and hashicorp/null.",
+			},
+		},
+	})
+	Register(&externalDataSourceRule{
+		info: &RuleInfo{
+			ID:              ruleIDExternalData,
+			Name:            "external-data-source",
+			Description:     "Terraform 'external' data sources run a program on the machine running Terraform, even during 'terraform plan'.",
+			DefaultSeverity: SeverityError,
+			Message:         `Data source "external" runs a program on the machine running Terraform, even during terraform plan.`,
+			Remediation:     "Compute the value in a separate, reviewed build step and pass it in as a variable.",
+			DocsURL:         rulesDocsURL,
+		},
+	})
+	Register(&httpDataSourceRule{
+		info: &RuleInfo{
+			ID:              ruleIDHTTPData,
+			Name:            "http-data-source",
+			Description:     "Terraform 'http' data sources send requests from the machine running Terraform and must only reach allowed hosts.",
+			DefaultSeverity: SeverityError,
+			Message:         `Data source "http" sends a request to a host that is not allowed.`,
+			Remediation:     "Remove the data source, or add its host to the hosts option of the rule after review.",
+			DocsURL:         rulesDocsURL,
+			Options: map[string]string{
+				optionHosts: "Hosts that http data sources may send requests to, such as api.github.com or *.example.com. Defaults to none.",
+			},
+		},
+	})
+	Register(&provisionerResourceRule{
+		info: &RuleInfo{
+			ID:              ruleIDProvisionerResource,
+			Name:            "provisioner-resource",
+			Description:     "Terraform 'terraform_data' and 'null_resource' resources with provisioners only exist to run commands.",
+			DefaultSeverity: SeverityError,
+			Message:         "Resource manages no infrastructure and only exists to run its provisioners.",
+			Remediation:     "Remove the resource and move the commands into a separate, reviewed build step.",
+			DocsURL:         rulesDocsURL,
+		},
+	})
+}
+
+// lookupRule returns the metadata of the rule with the given name or ID, or
+// nil if there is no such rule.
+func lookupRule(nameOrID string) *RuleInfo {
+	if r := LookupRule(nameOrID); r != nil {
+		return r.Info()
+	}
+	return nil
+}
+
+// ruleNames returns the names of all registered rules.
+func ruleNames() []string {
+	rules := Rules()
+	names := make([]string, 0, len(rules))
+	for _, r := range rules {
+		names = append(names, r.Info().Name)
+	}
+	return names
+}
+
+// span is a range within a file. Lines and columns are 1-based and the end
+// position is exclusive.
+type span struct {
+	startLine, startColumn int
+	endLine, endColumn     int
+}
+
+// violationMessage returns the human readable message for a violation, falling
+// back to a generic message when the violation does not carry one.
+func violationMessage(v *ViolationInstance) string {
+	if v.Message != "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%q detected", v.ViolationType)
+}
+
+// sortViolations orders violations by their position in the file.
+func sortViolations(violations []*ViolationInstance) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Line != violations[j].Line {
+			return violations[i].Line < violations[j].Line
+		}
+		return violations[i].Column < violations[j].Column
+	})
+}
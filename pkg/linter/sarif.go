@@ -0,0 +1,291 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+const (
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion = "2.1.0"
+	sarifSrcRoot = "%SRCROOT%"
+
+	toolInformationURI = "https://github.com/abcxyz/secure-setup-terraform"
+
+	// sarifFingerprintKey names the fingerprint in partialFingerprints. The
+	// suffix is the fingerprint algorithm version.
+	sarifFingerprintKey = "secureSetupTerraform/v1"
+)
+
+// The types below model the subset of the SARIF 2.1.0 specification that is
+// needed to report violations to GitHub code scanning. See
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html.
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        *sarifTool         `json:"tool"`
+	Invocations []*sarifInvocation `json:"invocations,omitempty"`
+	Results     []*sarifResult     `json:"results"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful        bool                 `json:"executionSuccessful"`
+	ToolExecutionNotifications []*sarifNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+type sarifNotification struct {
+	Level     string           `json:"level"`
+	Message   *sarifMessage    `json:"message"`
+	Locations []*sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                 `json:"name"`
+	Version        string                 `json:"version,omitempty"`
+	InformationURI string                 `json:"informationUri,omitempty"`
+	Rules          []*sarifRuleDescriptor `json:"rules"`
+}
+
+type sarifRuleDescriptor struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name,omitempty"`
+	ShortDescription     *sarifMessage       `json:"shortDescription,omitempty"`
+	Help                 *sarifMessage       `json:"help,omitempty"`
+	HelpURI              string              `json:"helpUri,omitempty"`
+	DefaultConfiguration *sarifConfiguration `json:"defaultConfiguration,omitempty"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifResult struct {
+	RuleID       string              `json:"ruleId"`
+	RuleIndex    int                 `json:"ruleIndex"`
+	Level        string              `json:"level"`
+	Message      *sarifMessage       `json:"message"`
+	Locations    []*sarifLocation    `json:"locations"`
+	Suppressions []*sarifSuppression `json:"suppressions,omitempty"`
+
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion           `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+// writeSARIF writes the result to w as a SARIF 2.1.0 log. The driver lists the
+// rules that were enabled for the run. Suppressed violations are included with
+// an in-source suppression so code scanning can show them as dismissed.
+func writeSARIF(w io.Writer, res *Result) error {
+	rules := res.rules
+	if rules == nil {
+		rules = Rules()
+	}
+	driver := &sarifDriver{
+		Name:           version.Name,
+		Version:        version.Version,
+		InformationURI: toolInformationURI,
+		Rules:          make([]*sarifRuleDescriptor, 0, len(rules)),
+	}
+	ruleIndex := make(map[string]int, len(rules))
+	for i, r := range rules {
+		rule := r.Info()
+		ruleIndex[rule.ID] = i
+		driver.Rules = append(driver.Rules, &sarifRuleDescriptor{
+			ID:                   rule.ID,
+			Name:                 rule.Name,
+			ShortDescription:     &sarifMessage{Text: rule.Description},
+			Help:                 &sarifMessage{Text: rule.Remediation},
+			HelpURI:              rule.DocsURL,
+			DefaultConfiguration: &sarifConfiguration{Level: sarifLevel(rule.DefaultSeverity)},
+		})
+	}
+
+	results := make([]*sarifResult, 0, len(res.Violations)+len(res.Suppressed))
+	add := func(v *ViolationInstance, suppressions []*sarifSuppression) {
+		id := v.RuleID
+		if id == "" {
+			id = v.ViolationType
+		}
+		idx, ok := ruleIndex[id]
+		if !ok {
+			// Unknown rules still need a descriptor so the ruleIndex remains
+			// valid.
+			idx = len(driver.Rules)
+			ruleIndex[id] = idx
+			driver.Rules = append(driver.Rules, &sarifRuleDescriptor{ID: id, Name: v.ViolationType})
+		}
+		result := &sarifResult{
+			RuleID:       id,
+			RuleIndex:    idx,
+			Level:        sarifLevel(v.Severity),
+			Message:      &sarifMessage{Text: violationMessage(v)},
+			Locations:    []*sarifLocation{sarifLocationFor(v)},
+			Suppressions: suppressions,
+		}
+		if v.Fingerprint != "" {
+			result.PartialFingerprints = map[string]string{sarifFingerprintKey: v.Fingerprint}
+		}
+		results = append(results, result)
+	}
+	for _, v := range res.Violations {
+		add(v, nil)
+	}
+	for _, v := range res.Suppressed {
+		var suppressions []*sarifSuppression
+		for _, sup := range res.Suppressions {
+			if sup.Status == SuppressionActive && sup.covers(v) {
+				suppressions = append(suppressions, &sarifSuppression{Kind: "inSource", Justification: sup.Reason})
+			}
+		}
+		add(v, suppressions)
+	}
+
+	// Files that could not be linted are reported as tool notifications rather
+	// than results, since they are not violations of a rule.
+	invocation := &sarifInvocation{ExecutionSuccessful: len(res.Diagnostics) == 0}
+	for _, d := range res.Diagnostics {
+		var region *sarifRegion
+		if d.Line > 0 {
+			region = &sarifRegion{StartLine: d.Line, StartColumn: d.Column}
+		}
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, &sarifNotification{
+			Level:   "error",
+			Message: &sarifMessage{Text: d.Message},
+			Locations: []*sarifLocation{{
+				PhysicalLocation: &sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifactFor(d.Path),
+					Region:           region,
+				},
+			}},
+		})
+	}
+
+	log := &sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []*sarifRun{
+			{
+				Tool:        &sarifTool{Driver: driver},
+				Invocations: []*sarifInvocation{invocation},
+				Results:     results,
+			},
+		},
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(log); err != nil {
+		return fmt.Errorf("failed to encode sarif log: %w", err)
+	}
+	return nil
+}
+
+// sarifLocationFor builds the physical location of a violation. Relative paths
+// are reported against %SRCROOT% so code scanning can map them to the
+// repository.
+func sarifLocationFor(v *ViolationInstance) *sarifLocation {
+	artifact := sarifArtifactFor(v.Path)
+
+	var region *sarifRegion
+	if v.Line > 0 {
+		region = &sarifRegion{
+			StartLine:   v.Line,
+			StartColumn: v.Column,
+			EndLine:     v.EndLine,
+			EndColumn:   v.EndColumn,
+		}
+	}
+	loc := &sarifLocation{
+		PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: artifact,
+			Region:           region,
+		},
+	}
+	if v.Object != "" {
+		loc.LogicalLocations = []*sarifLogicalLocation{{FullyQualifiedName: v.Object}}
+	}
+	return loc
+}
+
+// sarifArtifactFor returns the artifact location for a path.
+func sarifArtifactFor(path string) *sarifArtifactLocation {
+	if filepath.IsAbs(path) {
+		return &sarifArtifactLocation{URI: "file://" + filepath.ToSlash(path)}
+	}
+	return &sarifArtifactLocation{
+		URI:       filepath.ToSlash(filepath.Clean(path)),
+		URIBaseID: sarifSrcRoot,
+	}
+}
+
+// sarifLevel converts a Severity to a SARIF result level.
+func sarifLevel(s Severity) string {
+	switch s {
+	case SeverityWarning:
+		return "warning"
+	case SeverityInfo:
+		return "note"
+	default:
+		return "error"
+	}
+}
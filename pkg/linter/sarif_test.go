@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		violations []*ViolationInstance
+		expect     []*sarifResult
+	}{
+		{
+			name:       "no violations",
+			violations: nil,
+			expect:     []*sarifResult{},
+		},
+		{
+			name: "relative and absolute paths",
+			violations: []*ViolationInstance{
+				newViolation("remote-exec", "modules/main.tf", span{23, 15, 23, 28}, "null_resource.echo"),
+				newViolation("setup-terraform", "/repo/.github/workflows/ci.yml", span{31, 15, 31, 83}, ""),
+			},
+			expect: []*sarifResult{
+				{
+					RuleID:    "SST002",
+					RuleIndex: 1,
+					Level:     "error",
+					Message:   &sarifMessage{Text: `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`},
+					Locations: []*sarifLocation{{
+						PhysicalLocation: &sarifPhysicalLocation{
+							ArtifactLocation: &sarifArtifactLocation{URI: "modules/main.tf", URIBaseID: "%SRCROOT%"},
+							Region:           &sarifRegion{StartLine: 23, StartColumn: 15, EndLine: 23, EndColumn: 28},
+						},
+						LogicalLocations: []*sarifLogicalLocation{{FullyQualifiedName: "null_resource.echo"}},
+					}},
+				},
+				{
+					RuleID:    "SST003",
+					RuleIndex: 2,
+					Level:     "error",
+					Message:   &sarifMessage{Text: `Step uses "hashicorp/setup-terraform" directly.`},
+					Locations: []*sarifLocation{{
+						PhysicalLocation: &sarifPhysicalLocation{
+							ArtifactLocation: &sarifArtifactLocation{URI: "file:///repo/.github/workflows/ci.yml"},
+							Region:           &sarifRegion{StartLine: 31, StartColumn: 15, EndLine: 31, EndColumn: 83},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			if err := writeSARIF(&b, &Result{Violations: tc.violations}); err != nil {
+				t.Fatal(err)
+			}
+
+			var got sarifLog
+			if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode sarif log: %v", err)
+			}
+			if got.Version != "2.1.0" {
+				t.Errorf("expected version 2.1.0, got %q", got.Version)
+			}
+			if len(got.Runs) != 1 {
+				t.Fatalf("expected 1 run, got %d", len(got.Runs))
+			}
+			if got, want := len(got.Runs[0].Tool.Driver.Rules), len(Rules()); got != want {
+				t.Errorf("expected %d rules, got %d", want, got)
+			}
+			if diff := cmp.Diff(tc.expect, got.Runs[0].Results); diff != "" {
+				t.Errorf("results (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteSARIF_DisabledRules(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseConfig([]byte(`
+version: 1
+rules:
+  remote-exec:
+    enabled: false
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := Run(context.Background(), &Options{
+		FS:      fstest.MapFS{"main.tf": {Data: []byte(testLocalExec)}},
+		Linters: []Linter{&TerraformLinter{}},
+		Config:  cfg,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b bytes.Buffer
+	if err := writeSARIF(&b, res); err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(b.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode sarif log: %v", err)
+	}
+
+	run := got.Runs[0]
+	ids := make(map[string]bool)
+	for _, r := range run.Tool.Driver.Rules {
+		ids[r.ID] = true
+	}
+	if !ids["SST001"] {
+		t.Errorf("expected a descriptor for the enabled local-exec rule, got %v", ids)
+	}
+	if ids["SST002"] {
+		t.Errorf("expected no descriptor for the disabled remote-exec rule, got %v", ids)
+	}
+	for _, r := range run.Results {
+		if got := run.Tool.Driver.Rules[r.RuleIndex].ID; got != r.RuleID {
+			t.Errorf("result for %s has ruleIndex %d pointing at %s", r.RuleID, r.RuleIndex, got)
+		}
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// suppressionDirective is the marker that starts a suppression comment, for
+// example:
+//
+//	# secure-terraform:ignore local-exec reason="reviewed in SEC-123" expires=2027-01-01
+const suppressionDirective = "secure-terraform:ignore"
+
+// suppressionDateLayout is the layout of the expires= value.
+const suppressionDateLayout = "2006-01-02"
+
+// violationTypeInvalidSuppression is the type of the violations reported for
+// suppression comments that name an unknown rule.
+const violationTypeInvalidSuppression = "invalid-suppression"
+
+// SuppressionStatus describes whether a suppression is in effect.
+type SuppressionStatus string
+
+const (
+	// SuppressionActive suppressions suppressed at least one violation.
+	SuppressionActive SuppressionStatus = "active"
+
+	// SuppressionUnused suppressions are valid but did not match a violation.
+	SuppressionUnused SuppressionStatus = "unused"
+
+	// SuppressionExpired suppressions are past their expiry date and no longer
+	// suppress anything.
+	SuppressionExpired SuppressionStatus = "expired"
+
+	// SuppressionInvalid suppressions are malformed, for example missing a
+	// reason or naming an unknown rule, and do not suppress anything.
+	SuppressionInvalid SuppressionStatus = "invalid"
+)
+
+// Suppression is an inline comment that suppresses violations of one or more
+// rules in the block or step that immediately follows it.
+type Suppression struct {
+	// Path and Line are the location of the comment.
+	Path string
+	Line int
+
+	// Rules are the rule names or IDs being suppressed.
+	Rules []string
+
+	// Reason is the mandatory justification for the suppression.
+	Reason string
+
+	// Expires is the date on which the suppression stops applying. It is the
+	// zero time if the suppression never expires.
+	Expires time.Time
+
+	// Status is the state of the suppression after it was applied.
+	Status SuppressionStatus
+
+	// Problem explains why an invalid suppression was rejected.
+	Problem string
+
+	// StartLine and EndLine are the lines covered by the suppression.
+	StartLine int
+	EndLine   int
+}
+
+// scoper is implemented by linters that can work out the extent of blocks or
+// steps, so suppressions can be attached to them and baseline fingerprints can
+// be computed from their content.
+type scoper interface {
+	// findScopedViolations is like FindViolations, but also returns the
+	// extent of the blocks or steps of the file so it is only parsed once.
+	findScopedViolations(content []byte, path string) ([]*ViolationInstance, scopes, error)
+}
+
+// scopes is the extent of the blocks or steps of a single file.
+type scopes interface {
+	// scopeEnd returns the last line of the construct starting at line.
+	scopeEnd(line int) int
+
+	// enclosingScope returns the first and last line of the innermost
+	// construct containing line.
+	enclosingScope(line int) (start, end int)
+}
+
+// findViolations runs the linter over content, returning the scopes of the
+// file if the linter can work them out.
+func findViolations(linter Linter, content []byte, path string) ([]*ViolationInstance, scopes, error) {
+	if s, ok := linter.(scoper); ok {
+		return s.findScopedViolations(content, path)
+	}
+	violations, err := linter.FindViolations(content, path)
+	return violations, nil, err
+}
+
+// findSuppressions returns all suppression comments in lines. The scopes, if
+// not nil, determine the lines covered by each suppression.
+func findSuppressions(lines []string, path string, sc scopes) []*Suppression {
+	var sups []*Suppression
+	for i, line := range lines {
+		text, ok := commentText(line)
+		if !ok {
+			continue
+		}
+		idx := strings.Index(text, suppressionDirective)
+		if idx < 0 {
+			continue
+		}
+
+		sup := parseSuppression(text[idx+len(suppressionDirective):])
+		sup.Path = path
+		sup.Line = i + 1
+
+		// Attach to the next line that is not blank or a comment.
+		target := 0
+		for j := i + 1; j < len(lines); j++ {
+			if _, isComment := commentText(lines[j]); isComment || strings.TrimSpace(lines[j]) == "" {
+				continue
+			}
+			target = j + 1
+			break
+		}
+		if target == 0 {
+			if sup.Problem == "" {
+				sup.Problem = "no block or step follows the suppression"
+			}
+		} else {
+			sup.StartLine, sup.EndLine = target, target
+			if sc != nil {
+				if end := sc.scopeEnd(target); end > target {
+					sup.EndLine = end
+				}
+			}
+		}
+		if sup.Problem != "" {
+			sup.Status = SuppressionInvalid
+		}
+		sups = append(sups, sup)
+	}
+	return sups
+}
+
+// parseSuppression parses the text following the suppression directive. Any
+// problems are recorded on the returned suppression.
+func parseSuppression(s string) *Suppression {
+	sup := &Suppression{}
+
+	fields, err := splitFields(s)
+	if err != nil {
+		sup.Problem = err.Error()
+		return sup
+	}
+	if len(fields) == 0 || strings.Contains(fields[0], "=") {
+		sup.Problem = "missing rule name"
+		return sup
+	}
+	for _, r := range strings.Split(fields[0], ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			sup.Rules = append(sup.Rules, r)
+		}
+	}
+
+	for _, f := range fields[1:] {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok {
+			sup.Problem = fmt.Sprintf("unexpected %q, expected key=value", f)
+			return sup
+		}
+		switch k {
+		case "reason":
+			sup.Reason = strings.TrimSpace(v)
+		case "expires":
+			t, err := time.Parse(suppressionDateLayout, v)
+			if err != nil {
+				sup.Problem = fmt.Sprintf("invalid expires date %q, expected YYYY-MM-DD", v)
+				return sup
+			}
+			sup.Expires = t
+		default:
+			sup.Problem = fmt.Sprintf("unknown key %q", k)
+			return sup
+		}
+	}
+
+	for _, r := range sup.Rules {
+		if LookupRule(r) == nil {
+			sup.Problem = fmt.Sprintf("unknown rule %q", r)
+			return sup
+		}
+	}
+	if sup.Reason == "" {
+		sup.Problem = `missing reason="..."`
+	}
+	return sup
+}
+
+// suppressionViolations returns a violation for every rule named in sups that
+// is not registered, so that a misspelled rule name fails the run instead of
+// silently leaving a violation unsuppressed.
+func suppressionViolations(sups []*Suppression) []*ViolationInstance {
+	var violations []*ViolationInstance
+	for _, sup := range sups {
+		for _, r := range sup.Rules {
+			if LookupRule(r) == nil {
+				violations = append(violations, &ViolationInstance{
+					ViolationType: violationTypeInvalidSuppression,
+					Severity:      SeverityError,
+					Message:       fmt.Sprintf("Suppression comment names unknown rule %q.", r),
+					Remediation:   "Use the name or ID of a registered rule, such as local-exec or SST001.",
+					Path:          sup.Path,
+					Line:          sup.Line,
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// splitFields splits s on whitespace, keeping double-quoted values (which may
+// contain spaces) together and unquoting them.
+func splitFields(s string) ([]string, error) {
+	var fields []string
+	s = strings.TrimSpace(s)
+	for s != "" {
+		end := strings.IndexAny(s, " \t\"")
+		if end < 0 {
+			fields = append(fields, s)
+			break
+		}
+		if s[end] != '"' {
+			fields = append(fields, s[:end])
+			s = strings.TrimSpace(s[end:])
+			continue
+		}
+
+		// Quoted value, find the closing quote honoring escapes.
+		prefix := s[:end]
+		quoted, err := strconv.QuotedPrefix(s[end:])
+		if err != nil {
+			return nil, fmt.Errorf("unterminated quoted value in %q", s)
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quoted value %s: %w", quoted, err)
+		}
+		fields = append(fields, prefix+value)
+		s = strings.TrimSpace(s[end+len(quoted):])
+	}
+	return fields, nil
+}
+
+// applySuppressions removes violations covered by a valid, unexpired
+// suppression, returning the remaining and the suppressed violations. The
+// status of every suppression is updated.
+func applySuppressions(violations []*ViolationInstance, sups []*Suppression, now time.Time) (remaining, suppressed []*ViolationInstance) {
+	for _, sup := range sups {
+		if sup.Status == SuppressionInvalid {
+			continue
+		}
+		sup.Status = SuppressionUnused
+		if !sup.Expires.IsZero() && !now.Before(sup.Expires) {
+			sup.Status = SuppressionExpired
+		}
+	}
+
+	for _, v := range violations {
+		matched := false
+		for _, sup := range sups {
+			if sup.Status != SuppressionUnused && sup.Status != SuppressionActive {
+				continue
+			}
+			if sup.covers(v) {
+				sup.Status = SuppressionActive
+				matched = true
+			}
+		}
+		if matched {
+			suppressed = append(suppressed, v)
+		} else {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining, suppressed
+}
+
+// covers returns true if the suppression applies to the violation.
+func (s *Suppression) covers(v *ViolationInstance) bool {
+	if s.Path != v.Path || v.Line < s.StartLine || v.Line > s.EndLine {
+		return false
+	}
+	for _, r := range s.Rules {
+		if r == v.ViolationType || strings.EqualFold(r, v.RuleID) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeSuppressionAudit writes a listing of every suppression to w.
+func writeSuppressionAudit(w io.Writer, sups []*Suppression) error {
+	for _, s := range sups {
+		line := fmt.Sprintf("%s:%d\t%s\t%s", s.Path, s.Line, s.Status, strings.Join(s.Rules, ","))
+		if s.Reason != "" {
+			line += fmt.Sprintf("\treason=%q", s.Reason)
+		}
+		if !s.Expires.IsZero() {
+			line += "\texpires=" + s.Expires.Format(suppressionDateLayout)
+		}
+		if s.Problem != "" {
+			line += "\tproblem=" + strconv.Quote(s.Problem)
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return fmt.Errorf("failed to write suppression audit: %w", err)
+		}
+	}
+	return nil
+}
+
+// commentText returns the text of a line that consists only of a '#' or '//'
+// comment.
+func commentText(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "#"):
+		return strings.TrimSpace(trimmed[1:]), true
+	case strings.HasPrefix(trimmed, "//"):
+		return strings.TrimSpace(trimmed[2:]), true
+	default:
+		return "", false
+	}
+}
+
+// splitLines splits content into lines without their line endings.
+func splitLines(content []byte) []string {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines
+}
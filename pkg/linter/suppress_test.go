@@ -0,0 +1,294 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuppressions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	suppressedResource := `
+# secure-terraform:ignore local-exec reason="reviewed by security" expires=2027-01-01
+resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+
+resource "null_resource" "other" {
+  provisioner "local-exec" {
+    command = "echo world"
+  }
+}
+`
+	suppressedProvisioner := `
+resource "null_resource" "echo" {
+  // secure-terraform:ignore SST002 reason="legacy host bootstrap"
+  provisioner "remote-exec" {
+    inline = ["echo hello"]
+  }
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	expired := `
+# secure-terraform:ignore local-exec reason="temporary" expires=2026-01-01
+resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	missingReason := `
+# secure-terraform:ignore local-exec
+resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	unknownRule := `
+# secure-terraform:ignore local-exce reason="typo"
+resource "aws_instance" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	workflow := `
+jobs:
+  build:
+    runs-on: 'ubuntu-latest'
+    steps:
+      # secure-terraform:ignore setup-terraform reason="migrating in #42"
+      - name: 'Setup Terraform'
+        uses: 'hashicorp/setup-terraform@v2'
+      - name: 'Setup Terraform again'
+        uses: 'hashicorp/setup-terraform@v2'
+`
+
+	cases := []struct {
+		name           string
+		linter         Linter
+		content        string
+		wantRemaining  []int
+		wantSuppressed []int
+		wantStatus     []SuppressionStatus
+	}{
+		{
+			name:           "suppresses whole resource",
+			linter:         &TerraformLinter{},
+			content:        suppressedResource,
+			wantRemaining:  []int{3, 3, 9, 10},
+			wantSuppressed: []int{4},
+			wantStatus:     []SuppressionStatus{SuppressionActive},
+		},
+		{
+			name:           "suppresses single provisioner by id",
+			linter:         &TerraformLinter{},
+			content:        suppressedProvisioner,
+			wantRemaining:  []int{2, 2, 7},
+			wantSuppressed: []int{4},
+			wantStatus:     []SuppressionStatus{SuppressionActive},
+		},
+		{
+			name:          "expired",
+			linter:        &TerraformLinter{},
+			content:       expired,
+			wantRemaining: []int{3, 3, 4},
+			wantStatus:    []SuppressionStatus{SuppressionExpired},
+		},
+		{
+			name:          "missing reason",
+			linter:        &TerraformLinter{},
+			content:       missingReason,
+			wantRemaining: []int{3, 3, 4},
+			wantStatus:    []SuppressionStatus{SuppressionInvalid},
+		},
+		{
+			name:          "unknown rule",
+			linter:        &TerraformLinter{},
+			content:       unknownRule,
+			wantRemaining: []int{4},
+			wantStatus:    []SuppressionStatus{SuppressionInvalid},
+		},
+		{
+			name:           "workflow step",
+			linter:         &GitHubActionLinter{},
+			content:        workflow,
+			wantRemaining:  []int{10},
+			wantSuppressed: []int{8},
+			wantStatus:     []SuppressionStatus{SuppressionActive},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			violations, sc, err := findViolations(tc.linter, []byte(tc.content), "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			sups := findSuppressions(splitLines([]byte(tc.content)), "test", sc)
+			remaining, suppressed := applySuppressions(violations, sups, now)
+
+			if diff := cmp.Diff(tc.wantRemaining, violationLines(remaining)); diff != "" {
+				t.Errorf("remaining lines (-want,+got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantSuppressed, violationLines(suppressed)); diff != "" {
+				t.Errorf("suppressed lines (-want,+got):\n%s", diff)
+			}
+			var status []SuppressionStatus
+			for _, s := range sups {
+				status = append(status, s.Status)
+			}
+			if diff := cmp.Diff(tc.wantStatus, status); diff != "" {
+				t.Errorf("status (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseSuppression(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		input  string
+		expect *Suppression
+	}{
+		{
+			name:  "full",
+			input: ` local-exec,SST002 reason="reviewed, see \"SEC-1\"" expires=2027-01-01`,
+			expect: &Suppression{
+				Rules:   []string{"local-exec", "SST002"},
+				Reason:  `reviewed, see "SEC-1"`,
+				Expires: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:   "missing rule",
+			input:  ` reason="x"`,
+			expect: &Suppression{Problem: "missing rule name"},
+		},
+		{
+			name:  "bad date",
+			input: ` local-exec reason="x" expires=tomorrow`,
+			expect: &Suppression{
+				Rules:   []string{"local-exec"},
+				Reason:  "x",
+				Problem: `invalid expires date "tomorrow", expected YYYY-MM-DD`,
+			},
+		},
+		{
+			name:  "unknown key",
+			input: ` local-exec reason="x" owner=me`,
+			expect: &Suppression{
+				Rules:   []string{"local-exec"},
+				Reason:  "x",
+				Problem: `unknown key "owner"`,
+			},
+		},
+		{
+			name:  "unknown rule",
+			input: ` local-exec,local-exce reason="x"`,
+			expect: &Suppression{
+				Rules:   []string{"local-exec", "local-exce"},
+				Reason:  "x",
+				Problem: `unknown rule "local-exce"`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expect, parseSuppression(tc.input)); diff != "" {
+				t.Errorf("suppression (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRun_UnknownSuppressionRule(t *testing.T) {
+	t.Parallel()
+
+	content := `
+# secure-terraform:ignore local-exce reason="typo"
+resource "aws_instance" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+	res, err := Run(context.Background(), &Options{
+		FS:      fstest.MapFS{"main.tf": {Data: []byte(content)}},
+		Linters: []Linter{&TerraformLinter{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := res.Stats.ParseErrors; got != 0 {
+		t.Errorf("expected no parse errors, got %d: %v", got, res.Diagnostics)
+	}
+	var got []string
+	for _, v := range res.Violations {
+		got = append(got, fmt.Sprintf("%d %s: %s", v.Line, v.ViolationType, violationMessage(v)))
+	}
+	want := []string{
+		`4 local-exec: Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+		`2 invalid-suppression: Suppression comment names unknown rule "local-exce".`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("violations (-want,+got):\n%s", diff)
+	}
+	var verr *ViolationsError
+	if err := res.Err(nil); !errors.As(err, &verr) || verr.Count != 2 {
+		t.Errorf("expected the run to fail with 2 violations, got %v", err)
+	}
+
+	var audit bytes.Buffer
+	if err := writeSuppressionAudit(&audit, res.Suppressions); err != nil {
+		t.Fatal(err)
+	}
+	wantAudit := "main.tf:2\tinvalid\tlocal-exce\treason=\"typo\"\tproblem=\"unknown rule \\\"local-exce\\\"\"\n"
+	if diff := cmp.Diff(wantAudit, audit.String()); diff != "" {
+		t.Errorf("audit (-want,+got):\n%s", diff)
+	}
+}
+
+func violationLines(violations []*ViolationInstance) []int {
+	var lines []int
+	for _, v := range violations {
+		lines = append(lines, v.Line)
+	}
+	return lines
+}
@@ -0,0 +1,320 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
+)
+
+// jsonBlockSchemas are the nested blocks Terraform defines within each block
+// type, keyed by the type of the parent block and "" for the top level. The
+// JSON syntax does not distinguish blocks from object attributes, so only
+// these blocks are found in JSON files, while every block is found in native
+// syntax files.
+var jsonBlockSchemas = map[string]*hcl.BodySchema{
+	"": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "terraform"},
+			{Type: "provider", LabelNames: []string{"name"}},
+			{Type: "resource", LabelNames: []string{"type", "name"}},
+			{Type: "data", LabelNames: []string{"type", "name"}},
+			{Type: "ephemeral", LabelNames: []string{"type", "name"}},
+			{Type: "module", LabelNames: []string{"name"}},
+			{Type: "variable", LabelNames: []string{"name"}},
+			{Type: "output", LabelNames: []string{"name"}},
+			{Type: "locals"},
+			{Type: "check", LabelNames: []string{"name"}},
+			{Type: "import"},
+			{Type: "moved"},
+			{Type: "removed"},
+		},
+	},
+	"terraform": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "required_providers"},
+			{Type: "backend", LabelNames: []string{"type"}},
+			{Type: "cloud"},
+		},
+	},
+	"resource": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "lifecycle"},
+			{Type: "connection"},
+			{Type: "provisioner", LabelNames: []string{"type"}},
+		},
+	},
+	"data": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "lifecycle"},
+		},
+	},
+	"check": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "data", LabelNames: []string{"type", "name"}},
+			{Type: "assert"},
+		},
+	},
+	"removed": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "lifecycle"},
+			{Type: "connection"},
+			{Type: "provisioner", LabelNames: []string{"type"}},
+		},
+	},
+	"provisioner": {
+		Blocks: []hcl.BlockHeaderSchema{
+			{Type: "connection"},
+		},
+	},
+}
+
+// TerraformFile is a parsed Terraform configuration file.
+type TerraformFile struct {
+	// Path is the path of the file.
+	Path string
+
+	// Content is the raw content of the file.
+	Content []byte
+
+	// JSON is true if the file uses the JSON syntax.
+	JSON bool
+
+	// Body is the parsed body of the file. It is an *hclsyntax.Body for native
+	// syntax files.
+	Body hcl.Body
+
+	// Blocks are the top-level blocks of the file in source order.
+	Blocks []*Block
+
+	// moduleProviders are the local names of the providers required by any
+	// file of the module the file belongs to. It is nil if the other files of
+	// the module are unknown.
+	moduleProviders []string
+}
+
+// Block is a block of a Terraform configuration, such as a resource or a
+// provisioner within a resource.
+type Block struct {
+	// Type is the block type, for example "resource" or "provisioner".
+	Type string
+
+	// Labels are the labels of the block, for example the resource type and
+	// name.
+	Labels []string
+
+	// TypeRange is the range of the block type and LabelRanges the range of
+	// each label, including quotes.
+	TypeRange   hcl.Range
+	LabelRanges []hcl.Range
+
+	// DefRange is the range of the block header.
+	DefRange hcl.Range
+
+	// Range is the range of the whole block. In JSON files it spans the
+	// object holding the block's body.
+	Range hcl.Range
+
+	// Body is the body of the block, used to read its attributes.
+	Body hcl.Body
+
+	// Parent is the block containing this block, or nil for top-level blocks.
+	Parent *Block
+
+	// Blocks are the blocks nested in this block in source order.
+	Blocks []*Block
+
+	// json is true if the block was parsed from the JSON syntax.
+	json bool
+}
+
+// Address returns the address Terraform uses to refer to the top-level block
+// containing b, for example "null_resource.echo", "data.http.example" or
+// "module.network".
+func (b *Block) Address() string {
+	for b.Parent != nil {
+		b = b.Parent
+	}
+	if b.Type == "resource" {
+		return strings.Join(b.Labels, ".")
+	}
+	return strings.Join(append([]string{b.Type}, b.Labels...), ".")
+}
+
+// reportRange returns the range at which findings about the block are
+// reported: its first label, or its type if it has no labels. Blocks given as
+// an array in the JSON syntax share the key holding their label, so for JSON
+// blocks the start of the block's own object is used instead.
+func (b *Block) reportRange() hcl.Range {
+	switch {
+	case b.json:
+		return b.DefRange
+	case len(b.LabelRanges) > 0:
+		return b.LabelRanges[0]
+	default:
+		return b.TypeRange
+	}
+}
+
+// isProvisioner returns true if b is a provisioner of the given type.
+func (b *Block) isProvisioner(typ string) bool {
+	return b.Type == "provisioner" && b.Parent != nil && len(b.Labels) == 1 && b.Labels[0] == typ
+}
+
+// Inspect calls fn for every block of the file, parents before the blocks they
+// contain. Blocks nested in b are skipped when fn(b) returns false.
+func (f *TerraformFile) Inspect(fn func(b *Block) bool) {
+	inspectBlocks(f.Blocks, fn)
+}
+
+func inspectBlocks(blocks []*Block, fn func(b *Block) bool) {
+	for _, b := range blocks {
+		if fn(b) {
+			inspectBlocks(b.Blocks, fn)
+		}
+	}
+}
+
+// parseTerraform parses content with the native or the JSON syntax parser,
+// depending on the file extension, and builds its block tree.
+func parseTerraform(content []byte, path string) (*TerraformFile, error) {
+	if strings.HasSuffix(path, terraformJSONSuffix) {
+		file, diags := hcljson.Parse(content, path)
+		if diags.HasErrors() {
+			return nil, hclDiagnostic(path, diags)
+		}
+		return &TerraformFile{
+			Path:    path,
+			Content: content,
+			JSON:    true,
+			Body:    file.Body,
+			Blocks:  jsonBlocks(content, file.Body, nil),
+		}, nil
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, hclDiagnostic(path, diags)
+	}
+	body := file.Body.(*hclsyntax.Body)
+	return &TerraformFile{
+		Path:    path,
+		Content: content,
+		Body:    body,
+		Blocks:  nativeBlocks(body, nil),
+	}, nil
+}
+
+// nativeBlocks converts the blocks of a native syntax body.
+func nativeBlocks(body *hclsyntax.Body, parent *Block) []*Block {
+	blocks := make([]*Block, 0, len(body.Blocks))
+	for _, sb := range body.Blocks {
+		labelRanges := make([]hcl.Range, len(sb.LabelRanges))
+		copy(labelRanges, sb.LabelRanges)
+		b := &Block{
+			Type:        sb.Type,
+			Labels:      sb.Labels,
+			TypeRange:   sb.TypeRange,
+			LabelRanges: labelRanges,
+			DefRange:    sb.DefRange(),
+			Range:       sb.Range(),
+			Body:        sb.Body,
+			Parent:      parent,
+		}
+		b.Blocks = nativeBlocks(sb.Body, b)
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// jsonBlocks decodes the blocks of a JSON syntax body using jsonBlockSchemas.
+// Parts of the body that do not match the schema are skipped; Terraform
+// rejects them.
+func jsonBlocks(src []byte, body hcl.Body, parent *Block) []*Block {
+	typ := ""
+	if parent != nil {
+		typ = parent.Type
+	}
+	schema, ok := jsonBlockSchemas[typ]
+	if !ok {
+		return nil
+	}
+
+	content, _, _ := body.PartialContent(schema)
+	blocks := make([]*Block, 0, len(content.Blocks))
+	// Blocks given as an array all have the opening bracket of the array as
+	// their DefRange. Each entry's object starts after the bracket or after
+	// the previous entry, so arrays maps the start of each array to the end
+	// of its last entry seen so far.
+	arrays := make(map[int]hcl.Pos)
+	for _, hb := range content.Blocks {
+		defRange := hb.DefRange
+		end := hb.Body.MissingItemRange()
+		if start := defRange.Start.Byte; start < len(src) && src[start] == '[' {
+			from, ok := arrays[start]
+			if !ok {
+				from = defRange.End
+			}
+			if rng, ok := jsonObjectStart(src, from); ok {
+				rng.Filename = defRange.Filename
+				defRange = rng
+			}
+			arrays[start] = end.End
+		}
+
+		b := &Block{
+			Type:        hb.Type,
+			Labels:      hb.Labels,
+			TypeRange:   hb.TypeRange,
+			LabelRanges: hb.LabelRanges,
+			DefRange:    defRange,
+			Range:       hcl.RangeBetween(defRange, end),
+			Body:        hb.Body,
+			Parent:      parent,
+			json:        true,
+		}
+		b.Blocks = jsonBlocks(src, hb.Body, b)
+		blocks = append(blocks, b)
+	}
+	// Blocks are grouped by type in the schema order, restore the source order.
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return blocks[i].DefRange.Start.Byte < blocks[j].DefRange.Start.Byte
+	})
+	return blocks
+}
+
+// jsonObjectStart returns the range of the opening brace of the JSON object
+// following pos, skipping the whitespace and commas that separate array
+// entries. It returns false if anything else comes first.
+func jsonObjectStart(src []byte, pos hcl.Pos) (hcl.Range, bool) {
+	for pos.Byte < len(src) {
+		switch c := src[pos.Byte]; c {
+		case '{':
+			end := hcl.Pos{Line: pos.Line, Column: pos.Column + 1, Byte: pos.Byte + 1}
+			return hcl.Range{Start: pos, End: end}, true
+		case '\n':
+			pos = hcl.Pos{Line: pos.Line + 1, Column: 1, Byte: pos.Byte + 1}
+		case ' ', '\t', '\r', ',':
+			pos = hcl.Pos{Line: pos.Line, Column: pos.Column + 1, Byte: pos.Byte + 1}
+		default:
+			return hcl.Range{}, false
+		}
+	}
+	return hcl.Range{}, false
+}
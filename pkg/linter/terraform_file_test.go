@@ -0,0 +1,140 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTerraform_Blocks(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		filename  string
+		content   string
+		expect    []string
+		wantError bool
+	}{
+		{
+			name:     "native syntax",
+			filename: "main.tf",
+			content: `terraform {
+  required_providers {
+    null = { source = "hashicorp/null" }
+  }
+}
+
+module "network" {
+  source = "./network"
+}
+
+resource "null_resource" "echo" {
+  triggers = { provisioner = "local-exec" }
+
+  provisioner "local-exec" {
+    command = "echo hi"
+
+    connection {
+      host = "example.com"
+    }
+  }
+
+  dynamic "setting" {
+    for_each = []
+    content {}
+  }
+}
+`,
+			expect: []string{
+				"1-5 terraform [] in terraform",
+				"  2-4 required_providers [] in terraform",
+				"7-9 module [network] in module.network",
+				"11-26 resource [null_resource echo] in null_resource.echo",
+				"  14-20 provisioner [local-exec] in null_resource.echo",
+				"    17-19 connection [] in null_resource.echo",
+				"  22-25 dynamic [setting] in null_resource.echo",
+				"    24-24 content [] in null_resource.echo",
+			},
+		},
+		{
+			name:     "json syntax",
+			filename: "main.tf.json",
+			content: `{
+  "module": {"network": {"source": "./network"}},
+  "resource": {
+    "null_resource": {
+      "echo": {
+        "triggers": {"provisioner": "local-exec"},
+        "provisioner": {
+          "local-exec": {
+            "command": "echo hi",
+            "connection": {"host": "example.com"}
+          }
+        }
+      }
+    }
+  },
+  "data": {"http": {"example": {"url": "https://example.com"}}}
+}
+`,
+			expect: []string{
+				"2-2 module [network] in module.network",
+				"5-13 resource [null_resource echo] in null_resource.echo",
+				"  8-11 provisioner [local-exec] in null_resource.echo",
+				"    10-10 connection [] in null_resource.echo",
+				"16-16 data [http example] in data.http.example",
+			},
+		},
+		{
+			name:      "native syntax error",
+			filename:  "main.tf",
+			content:   `resource "null_resource" "echo" {`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parseTerraform([]byte(tc.content), tc.filename)
+			if tc.wantError != (err != nil) {
+				t.Fatalf("expected error want: %#v, got: %#v - error: %v", tc.wantError, err != nil, err)
+			}
+			if err != nil {
+				return
+			}
+
+			var got []string
+			file.Inspect(func(b *Block) bool {
+				depth := 0
+				for p := b.Parent; p != nil; p = p.Parent {
+					depth++
+				}
+				got = append(got, fmt.Sprintf("%s%d-%d %s %v in %s",
+					strings.Repeat("  ", depth), b.Range.Start.Line, b.Range.End.Line, b.Type, b.Labels, b.Address()))
+				return true
+			})
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("blocks (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -15,52 +15,200 @@
 package linter
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hclsyntax"
 )
 
 const (
 	tokenLocalExec  = "local-exec"
 	tokenRemoteExec = "remote-exec"
+	tokenFile       = "file"
+	tokenConnection = "connection"
 )
 
-var terraformSelectors = []string{".tf", ".tf.json"}
+// terraformJSONSuffix is the suffix of Terraform files written in the JSON
+// syntax.
+const terraformJSONSuffix = ".tf.json"
 
-type TerraformLinter struct{}
+var terraformSelectors = []string{".tf", terraformJSONSuffix}
 
-// FindViolations inspects a set of bytes that represent hcl from a terraform configuration file
-// looking for calls to the 'local-exec' provider.
+// TerraformLinter parses Terraform files and runs every enabled TerraformRule
+// against them.
+type TerraformLinter struct {
+	cfg *Config
+
+	// fsys and modules give access to the other files of the module of the
+	// file being linted. They are nil if only that file is known.
+	fsys    fileSystem
+	modules *moduleProviders
+}
+
+func (tfl *TerraformLinter) configure(cfg *Config) { tfl.cfg = cfg }
+
+func (tfl *TerraformLinter) useFileSystem(fsys fileSystem) {
+	tfl.fsys = fsys
+	tfl.modules = newModuleProviders(fsys)
+}
+
+// moduleKey returns the providers required by the module of the file at path,
+// which change the violations reported for it.
+func (tfl *TerraformLinter) moduleKey(path string) string {
+	if tfl.modules == nil {
+		return ""
+	}
+	return strings.Join(tfl.modules.names(tfl.fsys.dir(path)), ",")
+}
+
+// FindViolations parses a set of bytes that represent a terraform
+// configuration file and runs the enabled Terraform rules against them.
 func (tfl *TerraformLinter) FindViolations(content []byte, path string) ([]*ViolationInstance, error) {
-	tokens, diags := hclsyntax.LexConfig(content, path, hcl.Pos{Byte: 0, Line: 1, Column: 1})
-	if diags.HasErrors() {
-		diagMsg := diags.Error()
-		return nil, fmt.Errorf("error lexing hcl file contents: [%s]", diagMsg)
+	violations, _, err := tfl.findScopedViolations(content, path)
+	return violations, err
+}
+
+func (tfl *TerraformLinter) findScopedViolations(content []byte, path string) ([]*ViolationInstance, scopes, error) {
+	file, err := parseTerraform(content, path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tfl.modules != nil {
+		file.moduleProviders = tfl.modules.names(tfl.fsys.dir(path))
 	}
 
-	var instances []*ViolationInstance
-	inProvisioner := false
-	for _, token := range tokens {
-		if token.Bytes == nil {
+	var violations []*ViolationInstance
+	for _, r := range enabledRules(tfl.cfg) {
+		rule, ok := r.(TerraformRule)
+		if !ok {
 			continue
 		}
+		pass := &Pass{Path: path, info: rule.Info(), cfg: tfl.cfg}
+		rule.CheckTerraform(pass, file)
+		violations = append(violations, pass.violations...)
+	}
+	sortViolations(violations)
+	return violations, &terraformScopes{file: file}, nil
+}
+
+// provisionerRule reports provisioners of a single type.
+type provisionerRule struct {
+	info *RuleInfo
+
+	// provisioner is the provisioner type, for example "local-exec".
+	provisioner string
+}
+
+func (r *provisionerRule) Info() *RuleInfo { return r.info }
 
-		// Each Ident token starts a new object, we are only looking for provisioner
-		// type objects with specific types, local-exec and remote-exec
-		if token.Type == hclsyntax.TokenIdent {
-			inProvisioner = string(token.Bytes) == "provisioner"
+// CheckTerraform looks for provisioner blocks of the rule's type.
+func (r *provisionerRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	file.Inspect(func(b *Block) bool {
+		if b.isProvisioner(r.provisioner) {
+			pass.report(hclSpan(b.reportRange()), b.Address())
 		}
-		if inProvisioner && token.Type == hclsyntax.TokenQuotedLit {
-			if string(token.Bytes) == tokenLocalExec {
-				instances = append(instances, &ViolationInstance{ViolationType: tokenLocalExec, Path: path, Line: token.Range.Start.Line})
-			}
-			if string(token.Bytes) == tokenRemoteExec {
-				instances = append(instances, &ViolationInstance{ViolationType: tokenRemoteExec, Path: path, Line: token.Range.Start.Line})
+		return true
+	})
+}
+
+// provisionerResourceRule reports terraform_data and null_resource resources
+// with provisioners. These resources manage no infrastructure, so they only
+// exist to run commands.
+type provisionerResourceRule struct {
+	info *RuleInfo
+}
+
+func (r *provisionerResourceRule) Info() *RuleInfo { return r.info }
+
+// CheckTerraform looks for provisioner blocks in terraform_data and
+// null_resource resources, reporting each resource once.
+func (r *provisionerResourceRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	for _, b := range file.Blocks {
+		if b.Type != "resource" || len(b.Labels) != 2 {
+			continue
+		}
+		if typ := b.Labels[0]; typ != "terraform_data" && typ != "null_resource" {
+			continue
+		}
+		for _, nested := range b.Blocks {
+			if nested.Type == "provisioner" {
+				pass.report(hclSpan(b.reportRange()), b.Address())
+				break
 			}
 		}
 	}
-	return instances, nil
+}
+
+// connectionRule reports connection blocks, which open SSH or WinRM sessions
+// to remote hosts for provisioners.
+type connectionRule struct {
+	info *RuleInfo
+}
+
+func (r *connectionRule) Info() *RuleInfo { return r.info }
+
+// CheckTerraform looks for connection blocks in resources and provisioners.
+// Blocks named connection elsewhere in a resource belong to the provider's
+// schema and are not reported.
+func (r *connectionRule) CheckTerraform(pass *Pass, file *TerraformFile) {
+	file.Inspect(func(b *Block) bool {
+		if b.Type != tokenConnection || b.Parent == nil {
+			return true
+		}
+		parent := b.Parent
+		if parent.Type == "provisioner" {
+			parent = parent.Parent
+		}
+		if parent.Parent == nil && (parent.Type == "resource" || parent.Type == "removed") {
+			pass.report(hclSpan(b.reportRange()), b.Address())
+		}
+		return true
+	})
+}
+
+// hclSpan converts an hcl.Range to a span.
+func hclSpan(r hcl.Range) span {
+	return span{
+		startLine:   r.Start.Line,
+		startColumn: r.Start.Column,
+		endLine:     r.End.Line,
+		endColumn:   r.End.Column,
+	}
 }
 
 func (tfl *TerraformLinter) Selectors() []string { return terraformSelectors }
+
+// terraformScopes are the blocks of a parsed Terraform file.
+type terraformScopes struct {
+	file *TerraformFile
+}
+
+// scopeEnd returns the last line of the block that starts at line, or line if
+// no block starts there.
+func (s *terraformScopes) scopeEnd(line int) int {
+	end, found := line, false
+	s.file.Inspect(func(b *Block) bool {
+		if found {
+			return false
+		}
+		if b.Range.Start.Line == line {
+			end, found = b.Range.End.Line, true
+			return false
+		}
+		return line > b.Range.Start.Line && line <= b.Range.End.Line
+	})
+	return end
+}
+
+// enclosingScope returns the first and last line of the innermost block that
+// contains line.
+func (s *terraformScopes) enclosingScope(line int) (int, int) {
+	start, end := line, line
+	s.file.Inspect(func(b *Block) bool {
+		if line < b.Range.Start.Line || line > b.Range.End.Line {
+			return false
+		}
+		start, end = b.Range.Start.Line, b.Range.End.Line
+		return true
+	})
+	return start, end
+}
@@ -15,6 +15,7 @@
 package linter
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -126,6 +127,54 @@ func TestTerraformLinter_FindViolations(t *testing.T) {
 	}
 	`
 
+	jsonLocalExec := `{
+  "resource": {
+    "google_project_service": {
+      "local-exec": {
+        "service": "run.googleapis.com"
+      }
+    },
+    "null_resource": {
+      "echo": {
+        "provisioner": {
+          "local-exec": {
+            "command": "echo this is a bad practice"
+          }
+        }
+      }
+    }
+  }
+}
+`
+	jsonProvisionerArrays := `{
+  "resource": [
+    {
+      "null_resource": {
+        "echo": [
+          {
+            "provisioner": [
+              {"remote-exec": {"inline": ["true"]}},
+              {"local-exec": {"command": "echo first"}}
+            ]
+          }
+        ]
+      }
+    },
+    {
+      "terraform_data": {
+        "setup": {
+          "provisioner": {
+            "local-exec": [
+              {"command": "echo second"},
+              {"command": "echo third"}
+            ]
+          }
+        }
+      }
+    }
+  ]
+}
+`
 	cases := []struct {
 		name        string
 		filename    string
@@ -138,12 +187,46 @@ func TestTerraformLinter_FindViolations(t *testing.T) {
 			name:        "with local exec",
 			filename:    "/my/path/to/testfile1",
 			content:     withLocalExec,
-			expectCount: 1,
+			expectCount: 3,
 			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/testfile1",
+					Line:          22,
+					Column:        11,
+					EndLine:       22,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/testfile1",
+					Line:          22,
+					Column:        11,
+					EndLine:       22,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
 				{
 					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
 					Path:          "/my/path/to/testfile1",
 					Line:          23,
+					Column:        15,
+					EndLine:       23,
+					EndColumn:     27,
+					Object:        "null_resource.echo",
 				},
 			},
 			wantError: false,
@@ -168,16 +251,431 @@ func TestTerraformLinter_FindViolations(t *testing.T) {
 			name:        "with remote exec",
 			filename:    "/my/path/to/testfile1",
 			content:     withRemoteExec,
-			expectCount: 1,
+			expectCount: 3,
 			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/testfile1",
+					Line:          22,
+					Column:        11,
+					EndLine:       22,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/testfile1",
+					Line:          22,
+					Column:        11,
+					EndLine:       22,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
 				{
 					ViolationType: "remote-exec",
+					RuleID:        "SST002",
+					Severity:      SeverityError,
+					Message:       `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and configure the host with startup scripts or images instead.",
 					Path:          "/my/path/to/testfile1",
 					Line:          23,
+					Column:        15,
+					EndLine:       23,
+					EndColumn:     28,
+					Object:        "null_resource.echo",
 				},
 			},
 			wantError: false,
 		},
+		{
+			name:     "provisioner as attribute",
+			filename: "/my/path/to/main.tf",
+			content: `
+	resource "null_resource" "echo" {
+		triggers = {
+			provisioner = "local-exec"
+		}
+	}
+	locals {
+		provisioner = "remote-exec"
+	}
+	`,
+			expectCount: 1,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/main.tf",
+					Line:          2,
+					Column:        11,
+					EndLine:       2,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
+			},
+		},
+		{
+			name:     "connection in provider schema",
+			filename: "/my/path/to/main.tf",
+			content: `resource "google_bigquery_connection" "c" {
+  cloud_sql {
+    instance_id = "project:region:instance"
+
+    connection {
+      database = "app"
+    }
+  }
+}
+`,
+		},
+		{
+			name:     "file provisioner and connection",
+			filename: "/my/path/to/main.tf",
+			content: `resource "aws_instance" "web" {
+  connection {
+    type = "ssh"
+    host = self.public_ip
+  }
+
+  provisioner "file" {
+    source      = "conf/app.conf"
+    destination = "/etc/app.conf"
+
+    connection {
+      type = "winrm"
+    }
+  }
+}
+`,
+			expectCount: 3,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "connection",
+					RuleID:        "SST005",
+					Severity:      SeverityError,
+					Message:       "Connection block opens an SSH or WinRM session from the machine running Terraform.",
+					Remediation:   "Remove the connection and configure the host without connecting to it from Terraform.",
+					Path:          "/my/path/to/main.tf",
+					Line:          2,
+					Column:        3,
+					EndLine:       2,
+					EndColumn:     13,
+					Object:        "aws_instance.web",
+				},
+				{
+					ViolationType: "file-provisioner",
+					RuleID:        "SST004",
+					Severity:      SeverityError,
+					Message:       `Provisioner "file" copies files from the machine running Terraform to remote hosts.`,
+					Remediation:   "Remove the provisioner and ship the files in images or startup scripts instead.",
+					Path:          "/my/path/to/main.tf",
+					Line:          7,
+					Column:        15,
+					EndLine:       7,
+					EndColumn:     21,
+					Object:        "aws_instance.web",
+				},
+				{
+					ViolationType: "connection",
+					RuleID:        "SST005",
+					Severity:      SeverityError,
+					Message:       "Connection block opens an SSH or WinRM session from the machine running Terraform.",
+					Remediation:   "Remove the connection and configure the host without connecting to it from Terraform.",
+					Path:          "/my/path/to/main.tf",
+					Line:          11,
+					Column:        5,
+					EndLine:       11,
+					EndColumn:     15,
+					Object:        "aws_instance.web",
+				},
+			},
+		},
+		{
+			name:        "json syntax",
+			filename:    "/my/path/to/main.tf.json",
+			content:     jsonLocalExec,
+			expectCount: 3,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          9,
+					Column:        15,
+					EndLine:       9,
+					EndColumn:     16,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          9,
+					Column:        15,
+					EndLine:       9,
+					EndColumn:     16,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          11,
+					Column:        25,
+					EndLine:       11,
+					EndColumn:     26,
+					Object:        "null_resource.echo",
+				},
+			},
+		},
+		{
+			name:        "json syntax arrays",
+			filename:    "/my/path/to/main.tf.json",
+			content:     jsonProvisionerArrays,
+			expectCount: 7,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          6,
+					Column:        11,
+					EndLine:       6,
+					EndColumn:     12,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          6,
+					Column:        11,
+					EndLine:       6,
+					EndColumn:     12,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "remote-exec",
+					RuleID:        "SST002",
+					Severity:      SeverityError,
+					Message:       `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and configure the host with startup scripts or images instead.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          8,
+					Column:        31,
+					EndLine:       8,
+					EndColumn:     32,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          9,
+					Column:        30,
+					EndLine:       9,
+					EndColumn:     31,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          17,
+					Column:        18,
+					EndLine:       17,
+					EndColumn:     19,
+					Object:        "terraform_data.setup",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          20,
+					Column:        15,
+					EndLine:       20,
+					EndColumn:     16,
+					Object:        "terraform_data.setup",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf.json",
+					Line:          21,
+					Column:        15,
+					EndLine:       21,
+					EndColumn:     16,
+					Object:        "terraform_data.setup",
+				},
+			},
+		},
+		{
+			name:     "provisioner-only resources",
+			filename: "/my/path/to/main.tf",
+			content: `resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo one"
+  }
+  provisioner "local-exec" {
+    command = "echo two"
+  }
+}
+
+resource "terraform_data" "bootstrap" {
+  provisioner "remote-exec" {
+    inline = ["true"]
+  }
+}
+
+resource "terraform_data" "trigger" {
+  input = var.revision
+}
+
+resource "aws_instance" "web" {
+  provisioner "local-exec" {
+    command = "echo three"
+  }
+}
+`,
+			expectCount: 7,
+			expect: []*ViolationInstance{
+				{
+					ViolationType: "provider-source",
+					RuleID:        "SST006",
+					Severity:      SeverityError,
+					Message:       `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list.`,
+					Remediation:   "Remove the provider, or add it to the allow list of the rule after review.",
+					Path:          "/my/path/to/main.tf",
+					Line:          1,
+					Column:        10,
+					EndLine:       1,
+					EndColumn:     25,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf",
+					Line:          1,
+					Column:        10,
+					EndLine:       1,
+					EndColumn:     25,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf",
+					Line:          2,
+					Column:        15,
+					EndLine:       2,
+					EndColumn:     27,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf",
+					Line:          5,
+					Column:        15,
+					EndLine:       5,
+					EndColumn:     27,
+					Object:        "null_resource.echo",
+				},
+				{
+					ViolationType: "provisioner-resource",
+					RuleID:        "SST009",
+					Severity:      SeverityError,
+					Message:       "Resource manages no infrastructure and only exists to run its provisioners.",
+					Remediation:   "Remove the resource and move the commands into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf",
+					Line:          10,
+					Column:        10,
+					EndLine:       10,
+					EndColumn:     26,
+					Object:        "terraform_data.bootstrap",
+				},
+				{
+					ViolationType: "remote-exec",
+					RuleID:        "SST002",
+					Severity:      SeverityError,
+					Message:       `Provisioner "remote-exec" runs arbitrary commands on remote hosts from the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and configure the host with startup scripts or images instead.",
+					Path:          "/my/path/to/main.tf",
+					Line:          11,
+					Column:        15,
+					EndLine:       11,
+					EndColumn:     28,
+					Object:        "terraform_data.bootstrap",
+				},
+				{
+					ViolationType: "local-exec",
+					RuleID:        "SST001",
+					Severity:      SeverityError,
+					Message:       `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform.`,
+					Remediation:   "Remove the provisioner and move the command into a separate, reviewed build step.",
+					Path:          "/my/path/to/main.tf",
+					Line:          21,
+					Column:        15,
+					EndLine:       21,
+					EndColumn:     27,
+					Object:        "aws_instance.web",
+				},
+			},
+		},
+		{
+			name:      "json syntax error",
+			filename:  "/my/path/to/main.tf.json",
+			content:   `{"resource": `,
+			wantError: true,
+		},
 	}
 
 	for _, tc := range cases {
@@ -195,3 +693,23 @@ func TestTerraformLinter_FindViolations(t *testing.T) {
 		})
 	}
 }
+
+// findRuleViolations lints content and returns the violations of the rule with
+// the given ID as "line:column object: message".
+func findRuleViolations(t *testing.T, id, filename, content string, cfg *Config) []string {
+	t.Helper()
+
+	l := &TerraformLinter{}
+	l.configure(cfg)
+	violations, err := l.FindViolations([]byte(content), filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range violations {
+		if v.RuleID == id {
+			got = append(got, fmt.Sprintf("%d:%d %s: %s", v.Line, v.Column, v.Object, v.Message))
+		}
+	}
+	return got
+}
@@ -0,0 +1,454 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileJob is a file found by the walker that needs to be linted.
+type fileJob struct {
+	// index is the position of the file in walk order, used to keep the output
+	// deterministic regardless of which worker finishes first.
+	index  int
+	path   string
+	linter Linter
+
+	// diagnostic is set when the walker itself failed to read path, for example
+	// an unreadable directory. There is nothing to lint.
+	diagnostic *Diagnostic
+}
+
+// fileResult is the outcome of linting a single file.
+type fileResult struct {
+	index        int
+	path         string
+	violations   []*ViolationInstance
+	suppressions []*Suppression
+
+	// suppressed are the violations removed by a suppression comment, set by
+	// finish.
+	suppressed []*ViolationInstance
+
+	// scanned is true if path is a file that the linter attempted to read.
+	scanned bool
+
+	// cached is true if the result was read from the cache.
+	cached bool
+
+	// diagnostic is set if the file could not be read or parsed.
+	diagnostic *Diagnostic
+}
+
+// moduleLinter is implemented by linters whose violations in a file depend on
+// the other files of its directory, such as Terraform modules, which may
+// declare their providers in any of their files.
+type moduleLinter interface {
+	// useFileSystem gives the linter access to the files of the run.
+	useFileSystem(fsys fileSystem)
+
+	// moduleKey summarizes the other files that affect the violations of the
+	// file at path, so cached results are not reused after they change.
+	moduleKey(path string) string
+}
+
+// DefaultExcludes are the directory names that are skipped while walking
+// unless Options.NoDefaultExcludes is set. They hold version control metadata,
+// modules downloaded by "terraform init" and vendored third-party code.
+var DefaultExcludes = []string{".git", ".terraform", "vendor", "node_modules"}
+
+// lint walks the paths and lints every file matching one of the linters. Each
+// file is handled by the first linter whose selectors match it. The walk runs
+// in its own goroutine and feeds a pool of workers that read and parse files
+// concurrently. Results are returned in walk order, and each file is passed to
+// Options.onFile as soon as it and every file before it have been linted. The
+// first error, or cancellation of ctx, stops the walk and all workers.
+func lint(ctx context.Context, paths []string, linters []Linter, opts *Options) (*Result, error) {
+	now := time.Now()
+	workers := opts.Workers
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	var fsys fileSystem = osFileSystem{}
+	if opts.FS != nil {
+		fsys = ioFileSystem{fsys: opts.FS}
+	}
+	for _, linter := range linters {
+		if m, ok := linter.(moduleLinter); ok {
+			m.useFileSystem(fsys)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	jobs := make(chan *fileJob)
+	go func() {
+		defer close(jobs)
+		w := &walker{linters: linters, opts: opts, fsys: fsys, jobs: jobs}
+		for _, path := range paths {
+			if err := w.walkRoot(ctx, path); err != nil {
+				cancel(err)
+				return
+			}
+		}
+	}()
+
+	results := make(chan *fileResult)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				result := &fileResult{path: job.path, diagnostic: job.diagnostic}
+				if job.diagnostic == nil {
+					result = lintFile(fsys, job.path, job.linter, opts.Config, opts.Cache, opts.root)
+				}
+				result.index = job.index
+				results <- result
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Workers finish out of order, so results are held back until every file
+	// before them is done.
+	res := &Result{}
+	pending := make(map[int]*fileResult)
+	next := 0
+	for r := range results {
+		pending[r.index] = r
+		for ; pending[next] != nil && ctx.Err() == nil; next++ {
+			file := pending[next]
+			delete(pending, next)
+			file.finish(opts, now)
+			fileRes := file.result()
+			if opts.onFile != nil {
+				if err := opts.onFile(fileRes); err != nil {
+					cancel(err)
+				}
+			}
+			res.merge(fileRes)
+		}
+	}
+
+	if err := context.Cause(ctx); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// finish applies the suppression comments, the changes since Options.Since
+// and the baseline to the violations of the file.
+func (r *fileResult) finish(opts *Options, now time.Time) {
+	r.violations, r.suppressed = applySuppressions(r.violations, r.suppressions, now)
+	if opts.changes != nil && !opts.SinceWholeFile {
+		r.violations = opts.changes.filter(r.violations)
+		r.suppressed = opts.changes.filter(r.suppressed)
+	}
+	if opts.baseline != nil {
+		applyBaseline(r.violations, opts.baseline)
+	}
+}
+
+// result returns the outcome of the file as a Result of its own.
+func (r *fileResult) result() *Result {
+	res := &Result{
+		Violations:   r.violations,
+		Suppressions: r.suppressions,
+		Suppressed:   r.suppressed,
+	}
+	if r.scanned {
+		res.Files = []string{r.path}
+	}
+	if r.diagnostic != nil {
+		res.Diagnostics = []*Diagnostic{r.diagnostic}
+	}
+	if r.cached {
+		res.Stats.CacheHits = 1
+	}
+	return res
+}
+
+// walker walks directories in lexical order, sending every file that should be
+// linted to jobs.
+type walker struct {
+	linters []Linter
+	opts    *Options
+	fsys    fileSystem
+	jobs    chan<- *fileJob
+	next    int
+
+	// root is the path given to walkRoot. Include and exclude globs from
+	// Options are relative to it.
+	root string
+
+	// visited holds the resolved paths of directories reached through symlinks
+	// so that loops are only walked once.
+	visited map[string]struct{}
+}
+
+// walkRoot walks a path given by the user. Unlike paths found while walking,
+// it is always followed, even if it is a symlink or matches an exclusion.
+func (w *walker) walkRoot(ctx context.Context, path string) error {
+	w.root = path
+	w.visited = make(map[string]struct{})
+
+	info, err := w.fsys.stat(path)
+	if err != nil {
+		return fmt.Errorf("error reading file at path %q: error reading file information %w", path, err)
+	}
+	if !info.IsDir() {
+		return w.sendFile(ctx, path)
+	}
+	if real, err := w.fsys.evalSymlinks(path); err == nil {
+		w.visited[real] = struct{}{}
+	}
+	return w.walkDir(ctx, path, w.loadGitignore(nil, path))
+}
+
+func (w *walker) walkDir(ctx context.Context, dir string, ignores gitignoreStack) error {
+	entries, err := w.fsys.readDir(dir)
+	if err != nil {
+		return w.send(ctx, &fileJob{
+			path:       dir,
+			diagnostic: toDiagnostic(dir, fmt.Errorf("error reading directory: %w", err)),
+		})
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return context.Cause(ctx)
+		}
+
+		path := w.fsys.join(dir, entry.Name())
+		isDir := entry.IsDir()
+		if entry.Type()&fs.ModeSymlink != 0 {
+			if !w.opts.FollowSymlinks {
+				continue
+			}
+			info, err := w.fsys.stat(path)
+			if err != nil {
+				// Dangling symlinks are not an error, there is nothing to lint.
+				continue
+			}
+			isDir = info.IsDir()
+			if isDir {
+				real, err := w.fsys.evalSymlinks(path)
+				if err != nil {
+					continue
+				}
+				if _, ok := w.visited[real]; ok {
+					continue
+				}
+				w.visited[real] = struct{}{}
+			}
+		}
+
+		if w.skip(path, entry.Name(), isDir, ignores) {
+			continue
+		}
+		if isDir {
+			if err := w.walkDir(ctx, path, w.loadGitignore(ignores, path)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.sendFile(ctx, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip returns true if the file or directory found while walking should not
+// be linted.
+func (w *walker) skip(path, name string, isDir bool, ignores gitignoreStack) bool {
+	if isDir && !w.opts.NoDefaultExcludes {
+		for _, d := range DefaultExcludes {
+			if name == d {
+				return true
+			}
+		}
+	}
+	if w.configExcluded(path) {
+		return true
+	}
+	if len(w.opts.Exclude) > 0 {
+		if rel, err := filepath.Rel(w.root, path); err == nil && matchAnyGlob(w.opts.Exclude, filepath.ToSlash(rel)) {
+			return true
+		}
+	}
+	return ignores.ignored(path, isDir)
+}
+
+// sendFile queues path to be linted if it is selected by a linter and the
+// include globs.
+func (w *walker) sendFile(ctx context.Context, path string) error {
+	linter := selectLinter(w.linters, path)
+	if linter == nil {
+		return nil
+	}
+	if !w.configIncluded(path) {
+		return nil
+	}
+	if w.opts.changes != nil && !w.opts.changes.changed(path) {
+		return nil
+	}
+	if len(w.opts.Include) > 0 {
+		rel, err := filepath.Rel(w.root, path)
+		if err != nil || !matchAnyGlob(w.opts.Include, filepath.ToSlash(rel)) {
+			return nil
+		}
+	}
+
+	return w.send(ctx, &fileJob{path: path, linter: linter})
+}
+
+// configPath returns path relative to the directory of the project
+// configuration, or false if it is outside of it. Paths within an fs.FS are
+// relative to the root of the FS.
+func (w *walker) configPath(path string) (string, bool) {
+	if _, ok := w.fsys.(ioFileSystem); ok {
+		return path, true
+	}
+	return w.opts.Config.relPath(path)
+}
+
+// configExcluded returns true if path matches an exclude glob of the project
+// configuration.
+func (w *walker) configExcluded(path string) bool {
+	cfg := w.opts.Config
+	if cfg == nil || len(cfg.Exclude) == 0 {
+		return false
+	}
+	rel, ok := w.configPath(path)
+	return ok && cfg.excludes(rel)
+}
+
+// configIncluded returns true if path matches an include glob of the project
+// configuration, or there are no include globs. Files outside the directory of
+// the configuration cannot match its globs and are always included, so that
+// pointing at a configuration elsewhere does not silently skip every file.
+func (w *walker) configIncluded(path string) bool {
+	cfg := w.opts.Config
+	if cfg == nil || len(cfg.Include) == 0 {
+		return true
+	}
+	rel, ok := w.configPath(path)
+	return !ok || cfg.includes(rel)
+}
+
+// send queues a job for the workers, assigning it the next index.
+func (w *walker) send(ctx context.Context, job *fileJob) error {
+	job.index = w.next
+	select {
+	case w.jobs <- job:
+		w.next++
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// loadGitignore returns ignores extended with the .gitignore file in dir, if
+// .gitignore handling is enabled and the file exists.
+func (w *walker) loadGitignore(ignores gitignoreStack, dir string) gitignoreStack {
+	if !w.opts.Gitignore {
+		return ignores
+	}
+	content, err := w.fsys.readFile(w.fsys.join(dir, gitignoreFileName))
+	if err != nil {
+		return ignores
+	}
+	next := make(gitignoreStack, len(ignores), len(ignores)+1)
+	copy(next, ignores)
+	return append(next, parseGitignore(dir, content))
+}
+
+// lintFile reads a single file and finds its violations and suppressions.
+// Failures to read or parse the file are returned as a diagnostic. When cache
+// is not nil, results are reused for files that have not changed. Fingerprints
+// use the path of the file relative to root.
+func lintFile(fsys fileSystem, path string, linter Linter, cfg *Config, cache *Cache, root string) *fileResult {
+	result := &fileResult{path: path, scanned: true}
+
+	content, err := fsys.readFile(path)
+	if err != nil {
+		result.diagnostic = toDiagnostic(path, fmt.Errorf("error reading file: %w", err))
+		return result
+	}
+
+	var key string
+	if cache != nil {
+		key = cacheKey(path, root, content, linter, cfg)
+		if entry := cache.get(key); entry != nil {
+			result.violations = entry.Violations
+			result.suppressions = entry.Suppressions
+			result.diagnostic = entry.Diagnostic
+			result.cached = true
+			return result
+		}
+	}
+
+	results, sc, err := findViolations(linter, content, path)
+	if err != nil {
+		result.diagnostic = toDiagnostic(path, err)
+	} else {
+		lines := splitLines(content)
+		result.suppressions = findSuppressions(lines, path, sc)
+		results = append(cfg.apply(results), suppressionViolations(result.suppressions)...)
+		for _, v := range results {
+			v.Fingerprint = fingerprint(v, root, lines, sc)
+		}
+		result.violations = results
+	}
+
+	if cache != nil {
+		cache.put(key, &cacheEntry{
+			Violations:   result.violations,
+			Suppressions: result.suppressions,
+			Diagnostic:   result.diagnostic,
+		})
+	}
+	return result
+}
+
+// selectLinter returns the first linter with a selector matching path, or nil
+// if no linter handles the file.
+func selectLinter(linters []Linter, path string) Linter {
+	for _, linter := range linters {
+		for _, sel := range linter.Selectors() {
+			if strings.HasSuffix(path, sel) {
+				return linter
+			}
+		}
+	}
+	return nil
+}
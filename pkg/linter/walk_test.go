@@ -0,0 +1,303 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testLocalExec = `resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hello"
+  }
+}
+`
+
+// testLocalExecViolations is the number of violations in testLocalExec: the
+// implicit null provider, the resource that only runs provisioners and the
+// local-exec provisioner.
+const testLocalExecViolations = 3
+
+// writeTestFiles writes the files, keyed by slash-separated path, under dir.
+func writeTestFiles(tb testing.TB, dir string, files map[string]string) {
+	tb.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+func TestLint_DeterministicOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := make(map[string]string)
+	var want, wantViolations []string
+	for i := range 40 {
+		name := fmt.Sprintf("mod%02d/main.tf", i)
+		files[name] = testLocalExec
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		want = append(want, path)
+		for range testLocalExecViolations {
+			wantViolations = append(wantViolations, path)
+		}
+	}
+	files["README.md"] = "not terraform"
+	writeTestFiles(t, dir, files)
+
+	for _, workers := range []int{1, 8} {
+		res, err := lint(context.Background(), []string{dir}, []Linter{&TerraformLinter{}}, &Options{Workers: workers})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, res.Files); diff != "" {
+			t.Errorf("workers=%d files (-want,+got):\n%s", workers, diff)
+		}
+		var got []string
+		for _, v := range res.Violations {
+			got = append(got, v.Path)
+		}
+		if diff := cmp.Diff(wantViolations, got); diff != "" {
+			t.Errorf("workers=%d violations (-want,+got):\n%s", workers, diff)
+		}
+	}
+}
+
+func TestLint_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"main.tf": testLocalExec})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := lint(ctx, []string{dir}, []Linter{&TerraformLinter{}}, &Options{Workers: 4}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLint_Error(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	if _, err := lint(context.Background(), []string{filepath.Join(dir, "missing")}, []Linter{&TerraformLinter{}}, &Options{Workers: 4}); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestLint_Filtering(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"main.tf":                        testLocalExec,
+		".terraform/modules/x/main.tf":   testLocalExec,
+		".git/hooks/main.tf":             testLocalExec,
+		"vendor/github.com/x/main.tf":    testLocalExec,
+		"examples/basic/main.tf":         testLocalExec,
+		"modules/net/main.tf":            testLocalExec,
+		"modules/net/generated/main.tf":  testLocalExec,
+		"modules/net/generated/keep.tf":  testLocalExec,
+		".gitignore":                     "/examples/\n*.tmp\n",
+		"modules/net/.gitignore":         "generated/*\n!generated/keep.tf\n",
+		"modules/net/scratch.tf.tmp":     testLocalExec,
+		"modules/net/scratch/main.tf.tf": testLocalExec,
+	}
+
+	cases := []struct {
+		name   string
+		opts   *Options
+		expect []string
+	}{
+		{
+			name: "default excludes",
+			opts: &Options{},
+			expect: []string{
+				"examples/basic/main.tf",
+				"main.tf",
+				"modules/net/generated/keep.tf",
+				"modules/net/generated/main.tf",
+				"modules/net/main.tf",
+				"modules/net/scratch/main.tf.tf",
+			},
+		},
+		{
+			name: "no default excludes",
+			opts: &Options{NoDefaultExcludes: true},
+			expect: []string{
+				".git/hooks/main.tf",
+				".terraform/modules/x/main.tf",
+				"examples/basic/main.tf",
+				"main.tf",
+				"modules/net/generated/keep.tf",
+				"modules/net/generated/main.tf",
+				"modules/net/main.tf",
+				"modules/net/scratch/main.tf.tf",
+				"vendor/github.com/x/main.tf",
+			},
+		},
+		{
+			name: "exclude and include globs",
+			opts: &Options{
+				Exclude: []string{"examples/**", "scratch"},
+				Include: []string{"modules/**"},
+			},
+			expect: []string{
+				"modules/net/generated/keep.tf",
+				"modules/net/generated/main.tf",
+				"modules/net/main.tf",
+			},
+		},
+		{
+			name: "config outside the target",
+			opts: &Options{Config: &Config{
+				Include: []string{"modules/**"},
+				Exclude: []string{"examples"},
+				dir:     t.TempDir(),
+			}},
+			expect: []string{
+				"examples/basic/main.tf",
+				"main.tf",
+				"modules/net/generated/keep.tf",
+				"modules/net/generated/main.tf",
+				"modules/net/main.tf",
+				"modules/net/scratch/main.tf.tf",
+			},
+		},
+		{
+			name: "gitignore",
+			opts: &Options{Gitignore: true},
+			expect: []string{
+				"main.tf",
+				"modules/net/generated/keep.tf",
+				"modules/net/main.tf",
+				"modules/net/scratch/main.tf.tf",
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := lint(context.Background(), []string{dir}, []Linter{&TerraformLinter{}}, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range res.Files {
+				rel, err := filepath.Rel(dir, f)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, filepath.ToSlash(rel))
+			}
+			if diff := cmp.Diff(tc.expect, got); diff != "" {
+				t.Errorf("files (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLint_Symlinks(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{"modules/net/main.tf": testLocalExec})
+	// A loop back to the root and a second path to the same module.
+	if err := os.Symlink(dir, filepath.Join(dir, "modules", "net", "loop")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "modules", "net"), filepath.Join(dir, "net")); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		follow bool
+		expect int
+	}{
+		{name: "skipped by default", follow: false, expect: 1},
+		{name: "followed without looping", follow: true, expect: 2},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := lint(context.Background(), []string{dir}, []Linter{&TerraformLinter{}}, &Options{FollowSymlinks: tc.follow})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(res.Files); got != tc.expect {
+				t.Errorf("expected %d files, got %d: %q", tc.expect, got, res.Files)
+			}
+		})
+	}
+}
+
+func TestLint_CollectsDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, map[string]string{
+		"a/broken.tf":        "resource \"null_resource\" \"x\" {\n  a = ~b\n}\n",
+		"b/main.tf":          testLocalExec,
+		"c/not-workflow.yml": "key: [unclosed\n",
+	})
+
+	res, err := lint(context.Background(), []string{dir}, []Linter{&TerraformLinter{}, &GitHubActionLinter{}}, &Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	diagnostics := res.Diagnostics
+	violations := len(res.Violations)
+
+	if violations != testLocalExecViolations {
+		t.Errorf("expected the %d violations in the valid file to be reported, got %d", testLocalExecViolations, violations)
+	}
+	want := []*Diagnostic{
+		{
+			Path:    filepath.Join(dir, "a", "broken.tf"),
+			Line:    2,
+			Column:  7,
+			Message: `Unsupported operator: Bitwise operators are not supported. Did you mean boolean NOT ("!")?`,
+		},
+		{
+			Path:    filepath.Join(dir, "c", "not-workflow.yml"),
+			Line:    1,
+			Message: "did not find expected ',' or ']'",
+		},
+	}
+	if diff := cmp.Diff(want, diagnostics); diff != "" {
+		t.Errorf("diagnostics (-want,+got):\n%s", diff)
+	}
+}
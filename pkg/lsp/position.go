@@ -0,0 +1,93 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsp
+
+import (
+	"bytes"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// The linters report 1-based lines and columns counted in characters, and
+// fixes as byte offsets. LSP positions are 0-based and count UTF-16 code units
+// within a line.
+
+// linePosition converts a 1-based line and column into a position in content.
+// A column of 0 is the start of the line, and columns past the end of the line
+// are clamped to it.
+func linePosition(content []byte, line, column int) position {
+	if line < 1 {
+		return position{}
+	}
+	text, ok := lineAt(content, line)
+	if !ok {
+		return offsetPosition(content, len(content))
+	}
+	off := 0
+	for i := 1; i < column && off < len(text); i++ {
+		_, size := utf8.DecodeRune(text[off:])
+		off += size
+	}
+	return position{Line: line - 1, Character: utf16Len(text[:off])}
+}
+
+// lineEnd returns the position of the end of the 1-based line, excluding the
+// line terminator.
+func lineEnd(content []byte, line int) position {
+	if line < 1 {
+		line = 1
+	}
+	text, ok := lineAt(content, line)
+	if !ok {
+		return offsetPosition(content, len(content))
+	}
+	return position{Line: line - 1, Character: utf16Len(text)}
+}
+
+// offsetPosition converts a byte offset in content into a position.
+func offsetPosition(content []byte, offset int) position {
+	offset = min(max(offset, 0), len(content))
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n"))
+	start := bytes.LastIndexByte(before, '\n') + 1
+	return position{Line: line, Character: utf16Len(before[start:])}
+}
+
+// lineAt returns the 1-based line of content without its line terminator, or
+// false if content has fewer lines.
+func lineAt(content []byte, line int) ([]byte, bool) {
+	for i := 1; i < line; i++ {
+		idx := bytes.IndexByte(content, '\n')
+		if idx < 0 {
+			return nil, false
+		}
+		content = content[idx+1:]
+	}
+	if idx := bytes.IndexByte(content, '\n'); idx >= 0 {
+		content = content[:idx]
+	}
+	return bytes.TrimSuffix(content, []byte("\r")), true
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode b.
+func utf16Len(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		n += utf16.RuneLen(r)
+		b = b[size:]
+	}
+	return n
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsp
+
+// The types below are the subset of the Language Server Protocol 3.17 used by
+// the server. See
+// https://microsoft.github.io/language-server-protocol/specifications/lsp/3.17/specification/.
+
+// Diagnostic severities.
+const (
+	severityError       = 1
+	severityWarning     = 2
+	severityInformation = 3
+)
+
+// textDocumentSyncFull means documents are synced by always sending their full
+// content.
+const textDocumentSyncFull = 1
+
+// codeActionQuickFix is the kind of code actions that fix a diagnostic.
+const codeActionQuickFix = "quickfix"
+
+type initializeResult struct {
+	Capabilities *serverCapabilities `json:"capabilities"`
+	ServerInfo   *serverInfo         `json:"serverInfo"`
+}
+
+type serverCapabilities struct {
+	TextDocumentSync   *textDocumentSyncOptions `json:"textDocumentSync"`
+	CodeActionProvider *codeActionOptions       `json:"codeActionProvider"`
+}
+
+type textDocumentSyncOptions struct {
+	OpenClose bool `json:"openClose"`
+	Change    int  `json:"change"`
+}
+
+type codeActionOptions struct {
+	CodeActionKinds []string `json:"codeActionKinds"`
+}
+
+type serverInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type textDocumentItem struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+	Text    string `json:"text"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type versionedTextDocumentIdentifier struct {
+	URI     string `json:"uri"`
+	Version int    `json:"version"`
+}
+
+type didOpenTextDocumentParams struct {
+	TextDocument *textDocumentItem `json:"textDocument"`
+}
+
+type didChangeTextDocumentParams struct {
+	TextDocument   *versionedTextDocumentIdentifier `json:"textDocument"`
+	ContentChanges []*textDocumentContentChange     `json:"contentChanges"`
+}
+
+type textDocumentContentChange struct {
+	Text string `json:"text"`
+}
+
+type didCloseTextDocumentParams struct {
+	TextDocument *textDocumentIdentifier `json:"textDocument"`
+}
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// before returns true if p is before other.
+func (p position) before(other position) bool {
+	return p.Line < other.Line || (p.Line == other.Line && p.Character < other.Character)
+}
+
+type lspRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+// overlaps returns true if r and other share at least one position. Ranges
+// are inclusive so that a cursor at the end of a diagnostic still matches.
+func (r lspRange) overlaps(other lspRange) bool {
+	return !r.End.before(other.Start) && !other.End.before(r.Start)
+}
+
+type diagnostic struct {
+	Range           lspRange         `json:"range"`
+	Severity        int              `json:"severity"`
+	Code            string           `json:"code,omitempty"`
+	CodeDescription *codeDescription `json:"codeDescription,omitempty"`
+	Source          string           `json:"source"`
+	Message         string           `json:"message"`
+}
+
+type codeDescription struct {
+	Href string `json:"href"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string        `json:"uri"`
+	Version     *int          `json:"version,omitempty"`
+	Diagnostics []*diagnostic `json:"diagnostics"`
+}
+
+type codeActionParams struct {
+	TextDocument *textDocumentIdentifier `json:"textDocument"`
+	Range        lspRange                `json:"range"`
+}
+
+type codeAction struct {
+	Title       string         `json:"title"`
+	Kind        string         `json:"kind"`
+	Diagnostics []*diagnostic  `json:"diagnostics,omitempty"`
+	IsPreferred bool           `json:"isPreferred,omitempty"`
+	Edit        *workspaceEdit `json:"edit"`
+}
+
+type workspaceEdit struct {
+	Changes map[string][]*textEdit `json:"changes"`
+}
+
+type textEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
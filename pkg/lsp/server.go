@@ -0,0 +1,489 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lsp implements a Language Server Protocol server that reports
+// violations in open Terraform and workflow files while they are edited.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
+	"github.com/abcxyz/secure-setup-terraform/pkg/version"
+)
+
+// diagnosticSource is shown by editors next to each diagnostic.
+const diagnosticSource = "secure-terraform"
+
+// ErrExitWithoutShutdown is returned by Serve when the client sends the exit
+// notification without requesting a shutdown first.
+var ErrExitWithoutShutdown = errors.New("exit notification received before shutdown")
+
+// Server lints documents as they are opened and edited in an editor and
+// publishes the violations as diagnostics. Unlike the lint commands, it lints
+// the unsaved content of each document.
+type Server struct {
+	linters    []linter.Linter
+	configPath string
+
+	w           io.Writer
+	initialized bool
+	shutdown    bool
+	documents   map[string]*document
+}
+
+// document is an open text document and its latest lint results.
+type document struct {
+	uri     string
+	path    string
+	version int
+	content []byte
+
+	// findings are the violations found in content with the diagnostic that
+	// was published for each of them.
+	findings []*finding
+}
+
+type finding struct {
+	violation  *linter.ViolationInstance
+	diagnostic *diagnostic
+}
+
+// NewServer creates a server that lints documents with linters. Each document
+// is handled by the first linter whose selectors match its path. configPath is
+// the project configuration file to use; when empty, the nearest configuration
+// file above each document is used, as with the lint commands.
+func NewServer(linters []linter.Linter, configPath string) *Server {
+	return &Server{
+		linters:    linters,
+		configPath: configPath,
+		documents:  make(map[string]*document),
+	}
+}
+
+// Serve reads messages from r and writes responses and notifications to w
+// until the client sends the exit notification, r is closed or ctx is
+// canceled. Messages are handled one at a time.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	br := bufio.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		body, err := readMessage(br)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.replyError(nil, &responseError{Code: codeParseError, Message: fmt.Sprintf("invalid message: %v", err)}); err != nil {
+				return err
+			}
+			continue
+		}
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+
+		// Errors other than a responseError are failures to write to the
+		// client, which end the session.
+		result, err := s.handle(ctx, &req)
+		var rerr *responseError
+		if err != nil && !errors.As(err, &rerr) {
+			return err
+		}
+		if req.isNotification() {
+			// Notifications cannot be answered, so invalid ones are dropped like
+			// unknown notifications.
+			continue
+		}
+		if rerr != nil {
+			if err := s.replyError(req.ID, rerr); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeMessage(s.w, &response{JSONRPC: "2.0", ID: req.ID, Result: result}); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a request or notification and returns the result to send
+// back to the client.
+func (s *Server) handle(ctx context.Context, req *request) (any, error) {
+	if !s.initialized && req.Method != "initialize" {
+		return nil, &responseError{Code: codeServerNotInitialized, Message: "server is not initialized"}
+	}
+
+	switch req.Method {
+	case "initialize":
+		s.initialized = true
+		return &initializeResult{
+			Capabilities: &serverCapabilities{
+				TextDocumentSync:   &textDocumentSyncOptions{OpenClose: true, Change: textDocumentSyncFull},
+				CodeActionProvider: &codeActionOptions{CodeActionKinds: []string{codeActionQuickFix}},
+			},
+			ServerInfo: &serverInfo{Name: version.Name, Version: version.Version},
+		}, nil
+
+	case "initialized":
+		return nil, nil
+
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+
+	case "textDocument/didOpen":
+		var params didOpenTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if params.TextDocument == nil {
+			return nil, missingParam(req, "textDocument")
+		}
+		item := params.TextDocument
+		path, err := uriPath(item.URI)
+		if err != nil {
+			return nil, &responseError{Code: codeInvalidParams, Message: err.Error()}
+		}
+		doc := &document{uri: item.URI, path: path, version: item.Version, content: []byte(item.Text)}
+		s.documents[item.URI] = doc
+		return nil, s.lint(ctx, doc)
+
+	case "textDocument/didChange":
+		var params didChangeTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if params.TextDocument == nil {
+			return nil, missingParam(req, "textDocument")
+		}
+		doc, ok := s.documents[params.TextDocument.URI]
+		if !ok || len(params.ContentChanges) == 0 {
+			return nil, nil
+		}
+		// The server only supports full document sync, so the last change
+		// holds the whole document.
+		doc.version = params.TextDocument.Version
+		doc.content = []byte(params.ContentChanges[len(params.ContentChanges)-1].Text)
+		return nil, s.lint(ctx, doc)
+
+	case "textDocument/didClose":
+		var params didCloseTextDocumentParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if params.TextDocument == nil {
+			return nil, missingParam(req, "textDocument")
+		}
+		uri := params.TextDocument.URI
+		if _, ok := s.documents[uri]; !ok {
+			return nil, nil
+		}
+		delete(s.documents, uri)
+		return nil, s.publish(uri, nil, []*diagnostic{})
+
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if err := unmarshalParams(req, &params); err != nil {
+			return nil, err
+		}
+		if params.TextDocument == nil {
+			return nil, missingParam(req, "textDocument")
+		}
+		return s.codeActions(params.TextDocument.URI, params.Range), nil
+
+	default:
+		return nil, &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// lint lints the content of doc and publishes the results. Failures to load
+// the configuration, lint or parse the document are published as diagnostics,
+// so they are shown in the editor instead of being lost in the server log.
+func (s *Server) lint(ctx context.Context, doc *document) error {
+	doc.findings = nil
+	diagnostics := []*diagnostic{}
+
+	res, err := s.run(ctx, doc)
+	if err != nil {
+		diagnostics = append(diagnostics, &diagnostic{
+			Range:    lspRange{End: lineEnd(doc.content, 1)},
+			Severity: severityError,
+			Source:   diagnosticSource,
+			Message:  err.Error(),
+		})
+		return s.publish(doc.uri, &doc.version, diagnostics)
+	}
+
+	for _, v := range res.Violations {
+		d := violationDiagnostic(doc.content, v)
+		doc.findings = append(doc.findings, &finding{violation: v, diagnostic: d})
+		diagnostics = append(diagnostics, d)
+	}
+	for _, d := range res.Diagnostics {
+		start := linePosition(doc.content, d.Line, d.Column)
+		diagnostics = append(diagnostics, &diagnostic{
+			Range:    lspRange{Start: start, End: lineEnd(doc.content, start.Line+1)},
+			Severity: severityError,
+			Source:   diagnosticSource,
+			Message:  d.Message,
+		})
+	}
+	return s.publish(doc.uri, &doc.version, diagnostics)
+}
+
+// run lints the content of doc in memory.
+func (s *Server) run(ctx context.Context, doc *document) (*linter.Result, error) {
+	name, cfg, err := s.resolve(doc.path)
+	if err != nil {
+		return nil, err
+	}
+	files := s.moduleFiles(doc, name)
+	files[name] = doc.content
+	res, err := linter.Run(ctx, &linter.Options{
+		Paths:   []string{name},
+		Linters: s.linters,
+		FS:      linter.NewMemFS(files),
+		Config:  cfg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to lint %s: %w", doc.path, err)
+	}
+	return res, nil
+}
+
+// moduleFiles returns the other Terraform files in the directory of doc,
+// keyed by their name next to name in the in-memory file system. Terraform
+// treats the files of a directory as one module, so for example a provider
+// used in doc may be declared in another file. Open documents are used instead
+// of the saved files.
+func (s *Server) moduleFiles(doc *document, name string) map[string][]byte {
+	files := make(map[string][]byte)
+	if !isTerraformFile(doc.path) {
+		return files
+	}
+	dir := filepath.Dir(doc.path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isTerraformFile(entry.Name()) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		files[path.Join(path.Dir(name), entry.Name())] = content
+	}
+	for _, other := range s.documents {
+		if filepath.Dir(other.path) == dir && isTerraformFile(other.path) {
+			files[path.Join(path.Dir(name), filepath.Base(other.path))] = other.content
+		}
+	}
+	return files
+}
+
+// isTerraformFile returns true if name is a Terraform file in either syntax.
+func isTerraformFile(name string) bool {
+	return strings.HasSuffix(name, ".tf") || strings.HasSuffix(name, ".tf.json")
+}
+
+// resolve returns the name of the document in the in-memory file system used
+// to lint it and the configuration that applies to it. The name is relative
+// to the directory of the configuration file, so include and exclude globs
+// match as they do for the lint commands.
+func (s *Server) resolve(docPath string) (string, *linter.Config, error) {
+	name := path.Base(filepath.ToSlash(docPath))
+
+	cfgPath := s.configPath
+	if cfgPath == "" {
+		found, err := linter.FindConfig(docPath)
+		if err != nil {
+			return "", nil, err
+		}
+		if found == "" {
+			return name, nil, nil
+		}
+		cfgPath = found
+	}
+	cfg, err := linter.LoadConfig(cfgPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(cfgPath))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve %q: %w", cfgPath, err)
+	}
+	abs, err := filepath.Abs(docPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve %q: %w", docPath, err)
+	}
+	if rel, err := filepath.Rel(dir, abs); err == nil && fs.ValidPath(filepath.ToSlash(rel)) {
+		name = filepath.ToSlash(rel)
+	}
+	return name, cfg, nil
+}
+
+// codeActions returns a quick fix for each violation in the document at uri
+// that has a fix and overlaps rng.
+func (s *Server) codeActions(uri string, rng lspRange) []*codeAction {
+	actions := []*codeAction{}
+	doc, ok := s.documents[uri]
+	if !ok {
+		return actions
+	}
+	for _, f := range doc.findings {
+		fix := f.violation.Fix
+		if fix == nil || !f.diagnostic.Range.overlaps(rng) {
+			continue
+		}
+		edits := make([]*textEdit, 0, len(fix.Edits))
+		for _, e := range fix.Edits {
+			edits = append(edits, &textEdit{
+				Range:   lspRange{Start: offsetPosition(doc.content, e.Start), End: offsetPosition(doc.content, e.End)},
+				NewText: e.NewText,
+			})
+		}
+		actions = append(actions, &codeAction{
+			Title:       fix.Description,
+			Kind:        codeActionQuickFix,
+			Diagnostics: []*diagnostic{f.diagnostic},
+			IsPreferred: true,
+			Edit:        &workspaceEdit{Changes: map[string][]*textEdit{uri: edits}},
+		})
+	}
+	return actions
+}
+
+// publish sends the diagnostics of the document at uri to the client.
+func (s *Server) publish(uri string, version *int, diagnostics []*diagnostic) error {
+	return writeMessage(s.w, &notification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  &publishDiagnosticsParams{URI: uri, Version: version, Diagnostics: diagnostics},
+	})
+}
+
+func (s *Server) replyError(id json.RawMessage, rerr *responseError) error {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return writeMessage(s.w, &errorResponse{JSONRPC: "2.0", ID: id, Error: rerr})
+}
+
+// violationDiagnostic converts a violation into a diagnostic. Violations
+// without an end are shown until the end of their line.
+func violationDiagnostic(content []byte, v *linter.ViolationInstance) *diagnostic {
+	start := linePosition(content, v.Line, v.Column)
+	end := lineEnd(content, v.Line)
+	if v.EndLine > 0 {
+		end = linePosition(content, v.EndLine, v.EndColumn)
+	}
+
+	msg := v.Message
+	if msg == "" {
+		msg = fmt.Sprintf("%q detected", v.ViolationType)
+	}
+	if v.Object != "" {
+		msg += " (in " + v.Object + ")"
+	}
+	if v.Remediation != "" {
+		msg += "\n" + v.Remediation
+	}
+
+	code := v.RuleID
+	if code == "" {
+		code = v.ViolationType
+	}
+	d := &diagnostic{
+		Range:    lspRange{Start: start, End: end},
+		Severity: lspSeverity(v.Severity),
+		Code:     code,
+		Source:   diagnosticSource,
+		Message:  msg,
+	}
+	if rule := linter.LookupRule(v.RuleID); rule != nil && rule.Info().DocsURL != "" {
+		d.CodeDescription = &codeDescription{Href: rule.Info().DocsURL}
+	}
+	return d
+}
+
+// lspSeverity converts a linter severity into a diagnostic severity.
+func lspSeverity(s linter.Severity) int {
+	switch s {
+	case linter.SeverityWarning:
+		return severityWarning
+	case linter.SeverityInfo:
+		return severityInformation
+	default:
+		return severityError
+	}
+}
+
+// uriPath returns the file system path of a document URI. Documents that are
+// not files, such as unsaved buffers, are linted by the last element of their
+// URI path.
+func uriPath(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", fmt.Errorf("invalid document uri %q: %w", uri, err)
+	}
+	if u.Scheme != "file" {
+		return path.Base(u.Opaque + u.Path), nil
+	}
+	p := u.Path
+	// file:///C:/dir on Windows.
+	if len(p) >= 3 && p[0] == '/' && p[2] == ':' {
+		p = p[1:]
+	}
+	return filepath.FromSlash(p), nil
+}
+
+// missingParam returns the error for a request without a required parameter.
+func missingParam(req *request, name string) error {
+	return &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params for %s: missing %s", req.Method, name)}
+}
+
+// unmarshalParams decodes the params of req into v.
+func unmarshalParams(req *request, v any) error {
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: fmt.Sprintf("invalid params for %s: %v", req.Method, err)}
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/abcxyz/secure-setup-terraform/pkg/linter"
+)
+
+const testTerraform = `resource "null_resource" "echo" {
+  provisioner "local-exec" {
+    command = "echo hi"
+  }
+}
+`
+
+const testWorkflow = `jobs:
+  build:
+    runs-on: 'ubuntu-latest'
+    steps:
+      - uses: 'hashicorp/setup-terraform@v3'
+`
+
+// clientMessage is any message sent by the server, decoded for inspection.
+type clientMessage struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *responseError  `json:"error"`
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := "version: 1\nrules:\n  local-exec:\n    severity: 'warning'\n" +
+		"  setup-terraform:\n    options:\n      replacement: ['abcxyz/secure-setup-terraform@v1']\n"
+	if err := os.WriteFile(filepath.Join(dir, linter.ConfigFileName), []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tfURI := fileURI(filepath.Join(dir, "modules", "main.tf"))
+	workflowURI := fileURI(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+
+	var in bytes.Buffer
+	send := func(id int, method string, params any) {
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+		if id > 0 {
+			msg["id"] = id
+		}
+		if err := writeMessage(&in, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(1, "initialize", map[string]any{"capabilities": map[string]any{}})
+	send(0, "initialized", map[string]any{})
+	send(0, "textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": tfURI, "languageId": "terraform", "version": 1, "text": testTerraform},
+	})
+	send(0, "textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": workflowURI, "languageId": "yaml", "version": 1, "text": testWorkflow},
+	})
+	send(2, "textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": workflowURI},
+		"range":        lspRange{Start: position{Line: 4, Character: 20}, End: position{Line: 4, Character: 20}},
+		"context":      map[string]any{"diagnostics": []any{}},
+	})
+	send(0, "textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": workflowURI, "version": 2},
+		"contentChanges": []any{map[string]any{"text": "jobs: {}\n"}},
+	})
+	send(0, "textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": tfURI}})
+	send(3, "textDocument/hover", map[string]any{})
+	send(4, "shutdown", nil)
+	send(0, "exit", nil)
+
+	var out bytes.Buffer
+	if err := NewServer(linter.DefaultLinters(), "").Serve(context.Background(), &in, &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	var got []*clientMessage
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, &msg)
+	}
+	if len(got) != 8 {
+		t.Fatalf("expected 8 messages, got %d", len(got))
+	}
+
+	var initResult initializeResult
+	decode(t, got[0].Result, &initResult)
+	if diff := cmp.Diff(&serverCapabilities{
+		TextDocumentSync:   &textDocumentSyncOptions{OpenClose: true, Change: textDocumentSyncFull},
+		CodeActionProvider: &codeActionOptions{CodeActionKinds: []string{codeActionQuickFix}},
+	}, initResult.Capabilities); diff != "" {
+		t.Errorf("capabilities (-want,+got):\n%s", diff)
+	}
+
+	version := 1
+	workflowDiagnostic := &diagnostic{
+		Range:           lspRange{Start: position{Line: 4, Character: 14}, End: position{Line: 4, Character: 44}},
+		Severity:        severityError,
+		Code:            "SST003",
+		CodeDescription: &codeDescription{Href: "https://github.com/abcxyz/secure-setup-terraform#rules"},
+		Source:          diagnosticSource,
+		Message: `Step uses "hashicorp/setup-terraform" directly. (in jobs.build.steps[0])` + "\n" +
+			"Use 'abcxyz/secure-setup-terraform' with the same inputs instead.",
+	}
+	version2 := 2
+	wantPublished := []*publishDiagnosticsParams{
+		{
+			URI:     tfURI,
+			Version: &version,
+			Diagnostics: []*diagnostic{
+				{
+					Range:           lspRange{Start: position{Character: 9}, End: position{Character: 24}},
+					Severity:        severityError,
+					Code:            "SST006",
+					CodeDescription: &codeDescription{Href: "https://github.com/abcxyz/secure-setup-terraform#rules"},
+					Source:          diagnosticSource,
+					Message: `Provider "registry.terraform.io/hashicorp/null", used implicitly by "null_resource", is on the deny list. (in null_resource.echo)` + "\n" +
+						"Remove the provider, or add it to the allow list of the rule after review.",
+				},
+				{
+					Range:           lspRange{Start: position{Character: 9}, End: position{Character: 24}},
+					Severity:        severityError,
+					Code:            "SST009",
+					CodeDescription: &codeDescription{Href: "https://github.com/abcxyz/secure-setup-terraform#rules"},
+					Source:          diagnosticSource,
+					Message: "Resource manages no infrastructure and only exists to run its provisioners. (in null_resource.echo)\n" +
+						"Remove the resource and move the commands into a separate, reviewed build step.",
+				},
+				{
+					Range:           lspRange{Start: position{Line: 1, Character: 14}, End: position{Line: 1, Character: 26}},
+					Severity:        severityWarning,
+					Code:            "SST001",
+					CodeDescription: &codeDescription{Href: "https://github.com/abcxyz/secure-setup-terraform#rules"},
+					Source:          diagnosticSource,
+					Message: `Provisioner "local-exec" runs arbitrary commands on the machine running Terraform. (in null_resource.echo)` + "\n" +
+						"Remove the provisioner and move the command into a separate, reviewed build step.",
+				},
+			},
+		},
+		{URI: workflowURI, Version: &version, Diagnostics: []*diagnostic{workflowDiagnostic}},
+		{URI: workflowURI, Version: &version2, Diagnostics: []*diagnostic{}},
+		{URI: tfURI, Diagnostics: []*diagnostic{}},
+	}
+	var published []*publishDiagnosticsParams
+	for _, i := range []int{1, 2, 4, 5} {
+		if got[i].Method != "textDocument/publishDiagnostics" {
+			t.Fatalf("message %d: expected publishDiagnostics, got %q", i, got[i].Method)
+		}
+		var p publishDiagnosticsParams
+		decode(t, got[i].Params, &p)
+		published = append(published, &p)
+	}
+	if diff := cmp.Diff(wantPublished, published); diff != "" {
+		t.Errorf("published diagnostics (-want,+got):\n%s", diff)
+	}
+
+	var actions []*codeAction
+	decode(t, got[3].Result, &actions)
+	wantActions := []*codeAction{{
+		Title:       "Use abcxyz/secure-setup-terraform@v1",
+		Kind:        codeActionQuickFix,
+		Diagnostics: []*diagnostic{workflowDiagnostic},
+		IsPreferred: true,
+		Edit: &workspaceEdit{Changes: map[string][]*textEdit{
+			workflowURI: {{
+				Range:   lspRange{Start: position{Line: 4, Character: 15}, End: position{Line: 4, Character: 43}},
+				NewText: "abcxyz/secure-setup-terraform@v1",
+			}},
+		}},
+	}}
+	if diff := cmp.Diff(wantActions, actions); diff != "" {
+		t.Errorf("code actions (-want,+got):\n%s", diff)
+	}
+
+	if got[6].Error == nil || got[6].Error.Code != codeMethodNotFound {
+		t.Errorf("expected method not found for hover, got %#v", got[6])
+	}
+	if string(got[7].ID) != "4" || string(got[7].Result) != "null" {
+		t.Errorf("expected null shutdown result, got id=%s result=%s", got[7].ID, got[7].Result)
+	}
+}
+
+func TestServer_ModuleFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config := "version: 1\nrules:\n  provider-source:\n    options:\n      allow: ['cloudflare/cloudflare']\n"
+	versions := "terraform {\n  required_providers {\n    cloudflare = { source = \"cloudflare/cloudflare\" }\n  }\n}\n"
+	for name, content := range map[string]string{
+		linter.ConfigFileName: config,
+		"versions.tf":         versions,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resource := "resource \"cloudflare_record\" \"x\" {}\n"
+	// The provider is declared in another file of the same directory, but not
+	// for the module in the subdirectory.
+	mainURI := fileURI(filepath.Join(dir, "main.tf"))
+	otherURI := fileURI(filepath.Join(dir, "other", "main.tf"))
+
+	var in bytes.Buffer
+	for _, msg := range []map[string]any{
+		{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]any{"capabilities": map[string]any{}}},
+		{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": mainURI, "languageId": "terraform", "version": 1, "text": resource},
+		}},
+		{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]any{
+			"textDocument": map[string]any{"uri": otherURI, "languageId": "terraform", "version": 1, "text": resource},
+		}},
+		{"jsonrpc": "2.0", "id": 2, "method": "shutdown"},
+		{"jsonrpc": "2.0", "method": "exit"},
+	} {
+		if err := writeMessage(&in, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := NewServer(linter.DefaultLinters(), "").Serve(context.Background(), &in, &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	got := make(map[string][]string)
+	r := bufio.NewReader(&out)
+	for {
+		body, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg clientMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Method != "textDocument/publishDiagnostics" {
+			continue
+		}
+		var p publishDiagnosticsParams
+		decode(t, msg.Params, &p)
+		codes := []string{}
+		for _, d := range p.Diagnostics {
+			codes = append(codes, d.Code)
+		}
+		got[p.URI] = codes
+	}
+
+	want := map[string][]string{
+		mainURI:  {},
+		otherURI: {"SST006"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("diagnostic codes (-want,+got):\n%s", diff)
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	t.Parallel()
+
+	var in, out bytes.Buffer
+	if err := writeMessage(&in, map[string]any{"jsonrpc": "2.0", "method": "exit"}); err != nil {
+		t.Fatal(err)
+	}
+	err := NewServer(linter.DefaultLinters(), "").Serve(context.Background(), &in, &out)
+	if !errors.Is(err, ErrExitWithoutShutdown) {
+		t.Errorf("expected %v, got %v", ErrExitWithoutShutdown, err)
+	}
+}
+
+func TestReadMessage(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		input  string
+		expect string
+		err    string
+	}{
+		{
+			name:   "valid",
+			input:  "Content-Length: 2\r\n\r\n{}",
+			expect: "{}",
+		},
+		{
+			name:  "missing length",
+			input: "Content-Type: application/json\r\n\r\n{}",
+			err:   "message is missing the Content-Length header",
+		},
+		{
+			name:  "invalid length",
+			input: "Content-Length: -1\r\n\r\n",
+			err:   `invalid Content-Length "-1"`,
+		},
+		{
+			name:  "too large",
+			input: "Content-Length: 67108865\r\n\r\n",
+			err:   "Content-Length 67108865 exceeds the maximum message size of 67108864 bytes",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := readMessage(bufio.NewReader(bytes.NewBufferString(tc.input)))
+			if tc.err != "" {
+				if err == nil || err.Error() != tc.err {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, string(got)); diff != "" {
+				t.Errorf("body (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	t.Parallel()
+
+	// "é" is two bytes and one UTF-16 code unit, "𝄞" is four bytes and two
+	// UTF-16 code units.
+	content := []byte("ab\r\né𝄞x\n")
+
+	cases := []struct {
+		name   string
+		got    position
+		expect position
+	}{
+		{name: "line start", got: linePosition(content, 2, 1), expect: position{Line: 1, Character: 0}},
+		{name: "after multibyte", got: linePosition(content, 2, 3), expect: position{Line: 1, Character: 3}},
+		{name: "column past end", got: linePosition(content, 1, 10), expect: position{Line: 0, Character: 2}},
+		{name: "line end excludes cr", got: lineEnd(content, 1), expect: position{Line: 0, Character: 2}},
+		{name: "offset", got: offsetPosition(content, 10), expect: position{Line: 1, Character: 3}},
+		{name: "offset past end", got: offsetPosition(content, 100), expect: position{Line: 2, Character: 0}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tc.expect, tc.got); diff != "" {
+				t.Errorf("position (-want,+got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func decode(t *testing.T, b json.RawMessage, v any) {
+	t.Helper()
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatalf("failed to decode %s: %v", b, err)
+	}
+}
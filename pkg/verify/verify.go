@@ -0,0 +1,82 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verify checks that a terraform binary matches a published checksum.
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Checksums is the content of the terraform-checksums.json file published with
+// each release.
+type Checksums struct {
+	Versions []*Checksum `json:"versions"`
+}
+
+// Checksum is the expected checksum of a single terraform release.
+type Checksum struct {
+	Version         string `json:"version"`
+	ArchiveChecksum string `json:"archive_checksum"`
+	BinaryChecksum  string `json:"binary_checksum"`
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+}
+
+// LoadChecksums reads a checksums file from path.
+func LoadChecksums(path string) (*Checksums, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	var c Checksums
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse checksums %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+// Find returns the checksum for the given version, OS and architecture.
+func (c *Checksums) Find(version, goos, goarch string) (*Checksum, error) {
+	for _, v := range c.Versions {
+		if v.Version == version && v.OS == goos && v.Arch == goarch {
+			return v, nil
+		}
+	}
+	return nil, fmt.Errorf("no checksum for terraform %s (%s/%s)", version, goos, goarch)
+}
+
+// Binary verifies that the sha256 of the file at path matches the binary
+// checksum of want.
+func Binary(path string, want *Checksum) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open binary: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return fmt.Errorf("failed to read binary: %w", err)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want.BinaryChecksum {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", path, got, want.BinaryChecksum)
+	}
+	return nil
+}
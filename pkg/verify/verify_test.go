@@ -0,0 +1,89 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "terraform")
+	if err := os.WriteFile(binary, []byte("terraform"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	checksums := filepath.Join(dir, "checksums.json")
+	if err := os.WriteFile(checksums, []byte(`{"versions": [
+  {"version": "1.2.3", "binary_checksum": "d79d76b9b6d9fa4b8bd0e4e4f7c3e76c6e44e5f5bd3bfaa10ac7b5f2a7a4b1e0", "os": "linux", "arch": "arm64"},
+  {"version": "1.2.3", "binary_checksum": "94dc3ea57721d541aae09b7bf2368c1e20d4c89996ff6df4349d86048877c0e7", "os": "linux", "arch": "amd64"}
+]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadChecksums(checksums)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name      string
+		version   string
+		arch      string
+		wantError string
+	}{
+		{
+			name:    "match",
+			version: "1.2.3",
+			arch:    "amd64",
+		},
+		{
+			name:      "mismatch",
+			version:   "1.2.3",
+			arch:      "arm64",
+			wantError: "checksum mismatch",
+		},
+		{
+			name:      "unknown version",
+			version:   "9.9.9",
+			arch:      "amd64",
+			wantError: "no checksum for terraform 9.9.9 (linux/amd64)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			want, err := c.Find(tc.version, "linux", tc.arch)
+			if err == nil {
+				err = Binary(binary, want)
+			}
+			if tc.wantError == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+				t.Errorf("expected error containing %q, got %v", tc.wantError, err)
+			}
+		})
+	}
+}